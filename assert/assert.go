@@ -83,6 +83,24 @@ Package [gotest.tools/v3/assert/cmp] provides
 many common comparisons. Additional comparisons can be written to compare
 values in other ways. See the example Assert (CustomComparison).
 
+# Updating expected values
+
+When the tests are run with the -update flag, a failed [Equal] or [DeepEqual]
+updates the expected value in the source file instead of failing the test. The
+expected value may be a variable with a name that starts with expected, a field
+of a table test case, or a literal at the call site.
+
+	assert.Equal(t, actual, "old value")
+	// go test -update rewrites the call to
+	assert.Equal(t, actual, "new value")
+
+# Structured failure reports
+
+A [Reporter] registered with [RegisterReporter] receives the details of each
+failed assertion as a [Failure]. Setting the GOTESTTOOLS_REPORT_FILE
+environment variable to a filename appends every failure to that file as a
+line of JSON.
+
 # Automated migration from testify
 
 gty-migrate-from-testify is a command which translates Go source code from
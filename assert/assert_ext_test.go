@@ -101,6 +101,75 @@ for const inside function
 	})
 }
 
+func TestEqual_WithGoldenUpdateOfLiterals(t *testing.T) {
+	t.Run("string literal is updated", func(t *testing.T) {
+		patchUpdate(t)
+		restoreFile(t)
+
+		actual := "the new value"
+		assert.Equal(t, actual, "")
+
+		raw, err := os.ReadFile(fileName(t))
+		assert.NilError(t, err)
+
+		expected := `assert.Equal(t, actual, "the new value")`
+		assert.Assert(t, strings.Contains(string(raw), expected), "actual=%v", string(raw))
+	})
+
+	t.Run("int literal is updated with a conversion", func(t *testing.T) {
+		patchUpdate(t)
+		restoreFile(t)
+
+		var actual int64 = 42
+		assert.Equal(t, actual, 0)
+
+		raw, err := os.ReadFile(fileName(t))
+		assert.NilError(t, err)
+
+		expected := `assert.Equal(t, actual, int64(42))`
+		assert.Assert(t, strings.Contains(string(raw), expected), "actual=%v", string(raw))
+	})
+
+	t.Run("composite literal is updated", func(t *testing.T) {
+		patchUpdate(t)
+		restoreFile(t)
+
+		actual := map[string][]int{"b": {2, 3}, "a": {1}}
+		assert.DeepEqual(t, actual, map[string][]int{})
+
+		raw, err := os.ReadFile(fileName(t))
+		assert.NilError(t, err)
+
+		expected := `assert.DeepEqual(t, actual, map[string][]int{"a": {1}, "b": {2, 3}})`
+		assert.Assert(t, strings.Contains(string(raw), expected), "actual=%v", string(raw))
+	})
+
+	t.Run("field of table test case is updated", func(t *testing.T) {
+		patchUpdate(t)
+		restoreFile(t)
+
+		testcases := []struct {
+			input    string
+			expected int
+		}{
+			{input: "one", expected: 1},
+			{input: "three", expected: 0},
+			{input: "two", expected: 2},
+		}
+		for _, tc := range testcases {
+			assert.Equal(t, len(tc.input), tc.expected)
+		}
+
+		raw, err := os.ReadFile(fileName(t))
+		assert.NilError(t, err)
+
+		expected := `{input: "three", expected: 5},`
+		assert.Assert(t, strings.Contains(string(raw), expected), "actual=%v", string(raw))
+		expected = `{input: "one", expected: 3},`
+		assert.Assert(t, strings.Contains(string(raw), expected), "actual=%v", string(raw))
+	})
+}
+
 // expectedOne is updated by running the tests with -update
 var expectedOne = ``
 
@@ -114,6 +183,20 @@ func patchUpdate(t *testing.T) {
 	})
 }
 
+// restoreFile restores the original content of the test file at the end of
+// the test.
+func restoreFile(t *testing.T) {
+	t.Helper()
+	_, filename, _, ok := runtime.Caller(1)
+	assert.Assert(t, ok, "failed to get call stack")
+
+	raw, err := os.ReadFile(filename)
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		assert.NilError(t, os.WriteFile(filename, raw, 0o644))
+	})
+}
+
 func fileName(t *testing.T) string {
 	t.Helper()
 	_, filename, _, ok := runtime.Caller(1)
@@ -63,6 +63,73 @@ func TestAssert_WithBinaryExpression_Failures(t *testing.T) {
 	})
 }
 
+func TestAssert_WithCompoundExpression_Failures(t *testing.T) {
+	a, b := 1, 2
+	values := []int{1, 2}
+	ready := func() bool { return false }
+
+	t.Run("and", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, a == b && len(values) > 3)
+		expectFailNowed(t, fakeT, "assertion failed: expression is false: a == b && len(values) > 3")
+	})
+	t.Run("or", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, a == b || ready() || !(a < b))
+		expectFailNowed(t, fakeT, "assertion failed: a is not b, and ready() is false, and a < b is true")
+	})
+	t.Run("and with a true operand", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, a == 1 && len(values) > 3)
+		expectFailNowed(t, fakeT, "assertion failed: expression is false: a == 1 && len(values) > 3")
+	})
+	t.Run("nested", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, len(testName) > 0 && (ready() || values == nil))
+		expectFailNowed(t, fakeT,
+			"assertion failed: (ready() is false, and values is not nil)")
+	})
+	t.Run("nested and in or", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, ready() || (a > 0 && b < 0))
+		expectFailNowed(t, fakeT,
+			"assertion failed: ready() is false, and (a > 0 && b < 0) is false")
+	})
+	t.Run("constant operands are omitted", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, len("abc") == 3 && (a == b))
+		expectFailNowed(t, fakeT, "assertion failed: a is not b")
+	})
+	t.Run("first false constant operand", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		const minItems = maxTestItems - 1
+		Assert(fakeT, minItems > 0 && len(testName) < minItems && a == b)
+		expectFailNowed(t, fakeT,
+			`assertion failed: len(testName) (6) is >= minItems (2)`)
+	})
+	t.Run("operands before a false constant operand", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Assert(fakeT, a < b && len(values) > maxTestItems && maxTestItems > 5 && ready())
+		expectFailNowed(t, fakeT,
+			"assertion failed: expression is false: "+
+				"a < b && len(values) > maxTestItems && maxTestItems > 5 && ready()")
+	})
+	t.Run("or with constant operands", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var typed int64 = 4
+		Assert(fakeT, typed == maxTestWeight || testName == "other")
+		expectFailNowed(t, fakeT,
+			`assertion failed: typed is not maxTestWeight (10), and testName ("values") is not "other"`)
+	})
+}
+
+// constants used by TestAssert_WithCompoundExpression_Failures
+const (
+	maxTestItems        = 3
+	maxTestWeight int64 = maxTestItems*3 + 1
+	testName            = "values"
+)
+
 func TestAssertWithBoolIdent(t *testing.T) {
 	fakeT := &fakeTestingT{}
 
@@ -1,4 +1,12 @@
-/*Package cmp provides Comparisons for Assert and Check*/
+/*
+Package cmp provides Comparisons for Assert and Check.
+
+Structured documents are compared with [JSONEqual] and [XMLEqual]. Documents in
+other formats are compared with [DocumentEqual] and the unmarshal function of a
+library for the format. For example, YAML documents are compared with:
+
+	assert.Assert(t, cmp.DocumentEqual(actual, expected, yaml.Unmarshal))
+*/
 package cmp // import "gotest.tools/v3/assert/cmp"
 
 import (
@@ -10,6 +18,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/internal/format"
+	"gotest.tools/v3/internal/registry"
 )
 
 // Comparison is a function which compares values and returns [ResultSuccess] if
@@ -30,7 +39,7 @@ func DeepEqual(x, y interface{}, opts ...cmp.Option) Comparison {
 				result = ResultFailure(panicmsg)
 			}
 		}()
-		diff := cmp.Diff(x, y, opts...)
+		diff := cmp.Diff(x, y, append(registeredComparers(), opts...)...)
 		if diff == "" {
 			return ResultSuccess
 		}
@@ -38,6 +47,40 @@ func DeepEqual(x, y interface{}, opts ...cmp.Option) Comparison {
 	}
 }
 
+// registeredComparers returns a cmp.Comparer option for each comparer
+// registered with assert.RegisterComparer.
+func registeredComparers() []cmp.Option {
+	comparers := registry.Comparers()
+	opts := make([]cmp.Option, 0, len(comparers))
+	for _, comparer := range comparers {
+		opts = append(opts, cmp.Comparer(comparer))
+	}
+	return opts
+}
+
+// formatValue formats v using the formatter registered for the type with
+// assert.RegisterFormatter, or with %v if there is no formatter. The elements
+// of a slice or array are formatted using the formatter for the element type.
+func formatValue(v interface{}) string {
+	if formatted, ok := registry.Format(v); ok {
+		return formatted
+	}
+	value := reflect.ValueOf(v)
+	switch {
+	case !value.IsValid():
+	case value.Kind() == reflect.Slice && !value.IsNil(), value.Kind() == reflect.Array:
+		if !registry.HasFormatter(value.Type().Elem()) {
+			break
+		}
+		elems := make([]string, value.Len())
+		for i := range elems {
+			elems[i] = formatValue(value.Index(i).Interface())
+		}
+		return "[" + strings.Join(elems, " ") + "]"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 func handleCmpPanic(r interface{}) (string, bool) {
 	if r == nil {
 		return "", false
@@ -97,7 +140,13 @@ func Regexp(re RegexOrPattern, v string) Comparison {
 // Equal succeeds if x == y. See [gotest.tools/v3/assert.Equal] for full documentation.
 func Equal(x, y interface{}) Comparison {
 	return func() Result {
+		equal, ok, err := registry.Compare(x, y)
 		switch {
+		case err != nil:
+			return ResultFailure(err.Error())
+		case ok && equal:
+			return ResultSuccess
+		case ok:
 		case x == y:
 			return ResultSuccess
 		case isMultiLineStringCompare(x, y):
@@ -105,10 +154,10 @@ func Equal(x, y interface{}) Comparison {
 			return multiLineDiffResult(diff, x, y)
 		}
 		return ResultFailureTemplate(`
-			{{- printf "%v" .Data.x}} (
+			{{- formatValue .Data.x }} (
 				{{- with callArg 0 }}{{ formatNode . }} {{end -}}
 				{{- printf "%T" .Data.x -}}
-			) != {{ printf "%v" .Data.y}} (
+			) != {{ formatValue .Data.y }} (
 				{{- with callArg 1 }}{{ formatNode . }} {{end -}}
 				{{- printf "%T" .Data.y -}}
 			)`,
@@ -161,14 +210,15 @@ func Len(seq interface{}, expected int) Comparison {
 // [strings.Contains].
 // If collection is a Map, contains will succeed if item is a key in the map.
 // If collection is a slice or array, item is compared to each item in the
-// sequence using [reflect.DeepEqual].
+// sequence using [reflect.DeepEqual], or the comparer registered for the type
+// with [gotest.tools/v3/assert.RegisterComparer].
 func Contains(collection interface{}, item interface{}) Comparison {
 	return func() Result {
 		colValue := reflect.ValueOf(collection)
 		if !colValue.IsValid() {
 			return ResultFailure("nil does not contain items")
 		}
-		msg := fmt.Sprintf("%v does not contain %v", collection, item)
+		msg := fmt.Sprintf("%s does not contain %s", formatValue(collection), formatValue(item))
 
 		itemValue := reflect.ValueOf(item)
 		switch colValue.Type().Kind() {
@@ -189,7 +239,15 @@ func Contains(collection interface{}, item interface{}) Comparison {
 
 		case reflect.Slice, reflect.Array:
 			for i := 0; i < colValue.Len(); i++ {
-				if reflect.DeepEqual(colValue.Index(i).Interface(), item) {
+				elem := colValue.Index(i).Interface()
+				equal, ok, err := registry.Compare(elem, item)
+				switch {
+				case err != nil:
+					return ResultFailure(err.Error())
+				case ok && equal:
+					return ResultSuccess
+				}
+				if reflect.DeepEqual(elem, item) {
 					return ResultSuccess
 				}
 			}
@@ -0,0 +1,413 @@
+package cmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DocumentPathFilter is used by [JSONEqual], [XMLEqual], and [DocumentEqual]
+// to ignore parts of a document. The filter receives the path to a value and
+// returns true if the value should be ignored.
+//
+// The path starts with $ for the root of the document, followed by .key for
+// each object key, and [index] for each array index. Keys which are not valid
+// identifiers are quoted, ex: $["content-type"]. See
+// [gotest.tools/v3/assert/opt.DocumentPath] for a filter which matches paths
+// by pattern.
+type DocumentPathFilter func(path string) bool
+
+// JSONEqual succeeds if x and y are JSON documents that are semantically equal.
+// Formatting and the order of object keys are not part of the comparison.
+// Numbers are equal if they have the same value, so 1 is equal to 1.0.
+//
+// x is the actual document and y is the expected document, like the arguments
+// of [gotest.tools/v3/assert.Equal]. x and y may be either a string or []byte.
+// Values at paths which match any of the filters are ignored.
+//
+// The failure message lists the path of each value that is different, with the
+// actual value before the expected value:
+//
+//	$.items[3].name: "a" != "b"
+func JSONEqual(x, y interface{}, ignore ...DocumentPathFilter) Comparison {
+	return DocumentEqual(x, y, unmarshalJSON, ignore...)
+}
+
+// XMLEqual succeeds if x and y are XML documents that are semantically equal.
+// Formatting, whitespace between elements, and the order of attributes are not
+// part of the comparison.
+//
+// x and y may be either a string or []byte. Values at paths which match any
+// of the filters are ignored.
+//
+// Elements are compared as objects, where attributes are keys that start
+// with @, text content is the #text key, and child elements are keys with the
+// name of the element. Child elements with the same name are an array in the
+// order they appear in the document, ex: $.catalog.book[1].@id. A child element
+// that appears once is not an array, ex: $.catalog.book.@id. When an element
+// appears once in one document, and more than once in the other, the single
+// element is compared as the first item of the array, so the failure message
+// reports the missing items, ex: $.catalog.book[1]: <missing> != {...}. The
+// namespace
+// of an element is compared as the @xmlns key, when it is different from the
+// namespace of the parent element. The prefixes used for namespaces are not
+// compared.
+func XMLEqual(x, y interface{}, ignore ...DocumentPathFilter) Comparison {
+	return documentEqual(x, y, unmarshalXML, &documentDiff{ignore: ignore, singleItemArrays: true})
+}
+
+// DocumentEqual succeeds if x and y are equal documents after they are decoded
+// with unmarshal. DocumentEqual can be used to compare documents in formats
+// that are not supported by this package. For example, YAML documents can be
+// compared by passing yaml.Unmarshal from a YAML library.
+//
+// unmarshal must be able to decode into an *interface{}. Maps with
+// non-string keys are compared using the string form of the key.
+//
+// See [JSONEqual] for details about x, y, and the failure message.
+func DocumentEqual(
+	x, y interface{},
+	unmarshal func(data []byte, v interface{}) error,
+	ignore ...DocumentPathFilter,
+) Comparison {
+	return documentEqual(x, y, unmarshal, &documentDiff{ignore: ignore})
+}
+
+func documentEqual(
+	x, y interface{},
+	unmarshal func(data []byte, v interface{}) error,
+	d *documentDiff,
+) Comparison {
+	return func() Result {
+		docX, err := decodeDocument(x, unmarshal)
+		if err != nil {
+			return ResultFailure(fmt.Sprintf("failed to decode actual document (x): %s", err))
+		}
+		docY, err := decodeDocument(y, unmarshal)
+		if err != nil {
+			return ResultFailure(fmt.Sprintf("failed to decode expected document (y): %s", err))
+		}
+
+		d.compare("$", docX, docY)
+		if len(d.lines) == 0 {
+			return ResultSuccess
+		}
+		return ResultFailure("documents are not equal:\n" + strings.Join(d.lines, "\n"))
+	}
+}
+
+func decodeDocument(
+	doc interface{},
+	unmarshal func(data []byte, v interface{}) error,
+) (interface{}, error) {
+	var data []byte
+	value := reflect.ValueOf(doc)
+	switch {
+	case !value.IsValid():
+		return nil, errors.New("invalid type nil for document")
+	case value.Kind() == reflect.String:
+		data = []byte(value.String())
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		data = value.Bytes()
+	default:
+		return nil, fmt.Errorf("invalid type %T for document", doc)
+	}
+
+	var result interface{}
+	if err := unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return normalizeDocument(result), nil
+}
+
+// normalizeDocument converts the values returned by different decoders to a
+// common set of types so that they can be compared.
+func normalizeDocument(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = normalizeDocument(item)
+		}
+		return typed
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[fmt.Sprint(key)] = normalizeDocument(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = normalizeDocument(item)
+		}
+		return typed
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return json.Number(fmt.Sprint(typed))
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(typed), 'g', -1, 32))
+	case float64:
+		return json.Number(strconv.FormatFloat(typed, 'g', -1, 64))
+	}
+	return v
+}
+
+type documentDiff struct {
+	ignore []DocumentPathFilter
+	// singleItemArrays compares a value that is not an array with an array as
+	// if it were an array with a single item.
+	singleItemArrays bool
+	lines            []string
+}
+
+func (d *documentDiff) isIgnored(path string) bool {
+	for _, filter := range d.ignore {
+		if filter(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *documentDiff) report(path string, x, y string) {
+	d.lines = append(d.lines, fmt.Sprintf("%s: %s != %s", path, x, y))
+}
+
+const missingDocumentValue = "<missing>"
+
+func (d *documentDiff) compare(path string, x, y interface{}) {
+	if d.isIgnored(path) {
+		return
+	}
+	if d.singleItemArrays {
+		x, y = asSingleItemArray(x, y), asSingleItemArray(y, x)
+	}
+	switch typedX := x.(type) {
+	case map[string]interface{}:
+		if typedY, ok := y.(map[string]interface{}); ok {
+			d.compareObjects(path, typedX, typedY)
+			return
+		}
+	case []interface{}:
+		if typedY, ok := y.([]interface{}); ok {
+			d.compareArrays(path, typedX, typedY)
+			return
+		}
+	case json.Number:
+		if typedY, ok := y.(json.Number); ok && equalNumbers(typedX, typedY) {
+			return
+		}
+	default:
+		if reflect.DeepEqual(x, y) {
+			return
+		}
+	}
+	d.report(path, formatDocumentValue(x), formatDocumentValue(y))
+}
+
+func (d *documentDiff) compareObjects(path string, x, y map[string]interface{}) {
+	keys := make([]string, 0, len(x)+len(y))
+	for key := range x {
+		keys = append(keys, key)
+	}
+	for key := range y {
+		if _, ok := x[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + formatDocumentKey(key)
+		valueX, okX := x[key]
+		valueY, okY := y[key]
+		switch {
+		case !okX && !d.isIgnored(keyPath):
+			d.report(keyPath, missingDocumentValue, formatDocumentValue(valueY))
+		case !okY && !d.isIgnored(keyPath):
+			d.report(keyPath, formatDocumentValue(valueX), missingDocumentValue)
+		case okX && okY:
+			d.compare(keyPath, valueX, valueY)
+		}
+	}
+}
+
+func (d *documentDiff) compareArrays(path string, x, y []interface{}) {
+	for i := 0; i < len(x) || i < len(y); i++ {
+		indexPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(x) && !d.isIgnored(indexPath):
+			d.report(indexPath, missingDocumentValue, formatDocumentValue(y[i]))
+		case i >= len(y) && !d.isIgnored(indexPath):
+			d.report(indexPath, formatDocumentValue(x[i]), missingDocumentValue)
+		case i < len(x) && i < len(y):
+			d.compare(indexPath, x[i], y[i])
+		}
+	}
+}
+
+// asSingleItemArray returns v as an array with a single item when other is an
+// array, and v is not.
+func asSingleItemArray(v, other interface{}) interface{} {
+	_, isArray := v.([]interface{})
+	if _, otherIsArray := other.([]interface{}); otherIsArray && !isArray {
+		return []interface{}{v}
+	}
+	return v
+}
+
+func equalNumbers(x, y json.Number) bool {
+	if x == y {
+		return true
+	}
+	ratX, okX := new(big.Rat).SetString(string(x))
+	ratY, okY := new(big.Rat).SetString(string(y))
+	return okX && okY && ratX.Cmp(ratY) == 0
+}
+
+var identifierKey = regexp.MustCompile(`^[@#]?[A-Za-z_][A-Za-z0-9_]*$`)
+
+func formatDocumentKey(key string) string {
+	if identifierKey.MatchString(key) {
+		return "." + key
+	}
+	return fmt.Sprintf("[%q]", key)
+}
+
+func formatDocumentValue(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+// unmarshalJSON decodes numbers as json.Number, so that large integers are
+// not rounded.
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// unmarshalXML decodes an XML document into the same structure produced by
+// json.Unmarshal. See XMLEqual for details.
+func unmarshalXML(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		switch {
+		case errors.Is(err, io.EOF):
+			return errors.New("document has no root element")
+		case err != nil:
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			element, err := decodeXMLElement(decoder, start, "")
+			if err != nil {
+				return err
+			}
+			if err := endOfXMLDocument(decoder); err != nil {
+				return err
+			}
+			*(v.(*interface{})) = map[string]interface{}{start.Name.Local: element}
+			return nil
+		}
+	}
+}
+
+// endOfXMLDocument returns an error if there is an element or text after the
+// root element.
+func endOfXMLDocument(decoder *xml.Decoder) error {
+	for {
+		token, err := decoder.Token()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+		switch typed := token.(type) {
+		case xml.Comment, xml.ProcInst, xml.Directive:
+		case xml.CharData:
+			if len(bytes.TrimSpace(typed)) > 0 {
+				return errors.New("unexpected text after the root element")
+			}
+		default:
+			return errors.New("unexpected element after the root element")
+		}
+	}
+}
+
+// decodeXMLElement decodes the element that starts with start. parentSpace is
+// the namespace of the parent element.
+func decodeXMLElement(
+	decoder *xml.Decoder,
+	start xml.StartElement,
+	parentSpace string,
+) (interface{}, error) {
+	element := make(map[string]interface{})
+	if start.Name.Space != parentSpace {
+		element["@xmlns"] = start.Name.Space
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			// namespace declarations are compared as the namespace of the elements
+			continue
+		}
+		element["@"+attr.Name.Local] = attr.Value
+	}
+
+	text := new(strings.Builder)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch typed := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, typed, start.Name.Space)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(element, typed.Name.Local, child)
+		case xml.CharData:
+			text.Write(typed)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(element) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				element["#text"] = content
+			}
+			return element, nil
+		}
+	}
+}
+
+func addXMLChild(element map[string]interface{}, name string, child interface{}) {
+	existing, ok := element[name]
+	if !ok {
+		element[name] = child
+		return
+	}
+	if items, ok := existing.([]interface{}); ok {
+		element[name] = append(items, child)
+		return
+	}
+	element[name] = []interface{}{existing, child}
+}
@@ -0,0 +1,138 @@
+package cmp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONEqual(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		x := `{"name": "a", "items": [1, 2.0, {"id": 3}]}`
+		y := []byte(`{
+			"items": [1, 2, {"id": 3.0}],
+			"name": "a"
+		}`)
+		assertSuccess(t, JSONEqual(x, y)())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		x := `{"name": "a", "items": [{"name": "a"}, {"name": "b"}], "extra": true}`
+		y := `{"name": 1, "items": [{"name": "a"}, {"name": "c"}, {}], "content-type": null}`
+		expected := `documents are not equal:
+$["content-type"]: <missing> != null
+$.extra: true != <missing>
+$.items[1].name: "b" != "c"
+$.items[2]: <missing> != {}
+$.name: "a" != 1`
+		assertFailure(t, JSONEqual(x, y)(), expected)
+	})
+
+	t.Run("large integers are not rounded", func(t *testing.T) {
+		result := JSONEqual(`9007199254740993`, `9007199254740992`)()
+		assertFailure(t, result, "documents are not equal:\n$: 9007199254740993 != 9007199254740992")
+	})
+
+	t.Run("ignored paths", func(t *testing.T) {
+		x := `{"id": "one", "items": [{"id": 1, "name": "a"}]}`
+		y := `{"items": [{"id": 2, "name": "a"}]}`
+		ignore := func(path string) bool {
+			return path == "$.id" || strings.HasSuffix(path, "].id")
+		}
+		assertSuccess(t, JSONEqual(x, y, ignore)())
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		result := JSONEqual(`{"a": `, `{}`)()
+		assertFailure(t, result, "failed to decode actual document (x): unexpected EOF")
+
+		result = JSONEqual(`{}`, `{} {}`)()
+		assertFailure(t, result, "failed to decode expected document (y): invalid character after top-level value")
+
+		result = JSONEqual(`{}`, 3)()
+		assertFailure(t, result, "failed to decode expected document (y): invalid type int for document")
+	})
+}
+
+func TestXMLEqual(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		x := `<?xml version="1.0"?>
+<catalog>
+    <book id="1" lang="en"><title>First</title></book>
+    <book id="2"><title>Second</title></book>
+</catalog>`
+		y := `<catalog><book lang="en" id="1"><title> First </title></book>` +
+			`<book id="2"><title>Second</title></book></catalog>`
+		assertSuccess(t, XMLEqual(x, y)())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		x := `<catalog><book id="1"><title>First</title></book><book id="2"/></catalog>`
+		y := `<catalog><book id="1"><title>Other</title></book><book id="3"/></catalog>`
+		expected := `documents are not equal:
+$.catalog.book[0].title: "First" != "Other"
+$.catalog.book[1].@id: "2" != "3"`
+		assertFailure(t, XMLEqual(x, y)(), expected)
+	})
+
+	t.Run("repeated element", func(t *testing.T) {
+		x := `<catalog><book id="1"/></catalog>`
+		y := `<catalog><book id="1"/><book id="2"/></catalog>`
+		assertFailure(t, XMLEqual(x, y)(), `documents are not equal:
+$.catalog.book[1]: <missing> != {"@id":"2"}`)
+
+		x = `<catalog><book id="3"/><book id="1"/></catalog>`
+		y = `<catalog><book id="1"/></catalog>`
+		assertFailure(t, XMLEqual(x, y)(), `documents are not equal:
+$.catalog.book[0].@id: "3" != "1"
+$.catalog.book[1]: {"@id":"1"} != <missing>`)
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		x := `<a:feed xmlns:a="urn:one"><a:title>x</a:title></a:feed>`
+		y := `<feed xmlns="urn:one"><title>x</title></feed>`
+		assertSuccess(t, XMLEqual(x, y)())
+
+		y = `<feed xmlns="urn:two"><title>x</title></feed>`
+		assertFailure(t, XMLEqual(x, y)(), `documents are not equal:
+$.feed.@xmlns: "urn:one" != "urn:two"`)
+
+		x = `<feed xmlns="urn:one"><t:title xmlns:t="urn:title">x</t:title></feed>`
+		y = `<feed xmlns="urn:one"><title>x</title></feed>`
+		assertFailure(t, XMLEqual(x, y)(), `documents are not equal:
+$.feed.title: {"#text":"x","@xmlns":"urn:title"} != "x"`)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		result := XMLEqual(``, `<a/>`)()
+		assertFailure(t, result, "failed to decode actual document (x): document has no root element")
+
+		result = XMLEqual(`<a/>`, `<a/><b/>`)()
+		assertFailure(t, result,
+			"failed to decode expected document (y): unexpected element after the root element")
+
+		result = XMLEqual(`<a/> text`, `<a/>`)()
+		assertFailure(t, result,
+			"failed to decode actual document (x): unexpected text after the root element")
+
+		assertSuccess(t, XMLEqual("<a/>\n<!-- comment -->\n", `<a/>`)())
+	})
+}
+
+func TestDocumentEqual(t *testing.T) {
+	unmarshal := func(data []byte, v interface{}) error {
+		doc := make(map[interface{}]interface{})
+		for _, line := range strings.Split(string(data), "\n") {
+			if parts := strings.SplitN(line, ": ", 2); len(parts) == 2 {
+				doc[parts[0]] = parts[1]
+			}
+		}
+		*(v.(*interface{})) = doc
+		return nil
+	}
+
+	result := DocumentEqual("a: 1\nb: 2", "b: 2\na: 1", unmarshal)()
+	assertSuccess(t, result)
+
+	result = DocumentEqual("a: 1\nb: 2", "b: 3\na: 1", unmarshal)()
+	assertFailure(t, result, "documents are not equal:\n$.b: \"2\" != \"3\"")
+}
@@ -0,0 +1,163 @@
+package cmp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorAs succeeds if errors.As(err, target) returns true. When the comparison
+// succeeds target is set to the matching error, so that it can be used in
+// more assertions.
+//
+// target must be a non-nil pointer to either a type that implements error, or
+// to any interface type. See [errors.As] for details.
+//
+// Errors with an Unwrap() []error method, like those returned by errors.Join,
+// are searched in depth-first order.
+//
+// Example:
+//
+//	var pathErr *fs.PathError
+//	assert.Assert(t, cmp.ErrorAs(err, &pathErr))
+//	assert.Equal(t, pathErr.Path, "config.yaml")
+func ErrorAs(err error, target interface{}) Comparison {
+	return func() Result {
+		targetType, ok := errorAsTargetType(target)
+		if !ok {
+			return ResultFailure(fmt.Sprintf("invalid type for target: %T", target))
+		}
+		if err == nil {
+			return ResultFailure(fmt.Sprintf("error is nil, not %s", targetType))
+		}
+		for _, layer := range flattenErrorChain(err) {
+			if errors.As(layer.err, target) {
+				return ResultSuccess
+			}
+		}
+		return ResultFailure(fmt.Sprintf("no error in the chain is a %s\n", targetType) +
+			formatErrorChain(err))
+	}
+}
+
+func errorAsTargetType(target interface{}) (reflect.Type, bool) {
+	value := reflect.ValueOf(target)
+	if !value.IsValid() || value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, false
+	}
+	targetType := value.Type().Elem()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		return nil, false
+	}
+	return targetType, true
+}
+
+// ErrorChain succeeds if the errors in the chain of err match expected, in
+// order. The chain starts with err, followed by each error returned by
+// Unwrap. Errors with an Unwrap() []error method, like those returned by
+// errors.Join, are followed by each of the wrapped errors and their chains,
+// in depth-first order.
+//
+// Each item in expected may be one of:
+//
+//	string
+//
+// The message of the error, from the Error method, must be equal to the string.
+//
+//	reflect.Type
+//
+// The error must be of the type, or implement the interface type.
+//
+// Example:
+//
+//	assert.Assert(t, cmp.ErrorChain(err,
+//		"read config: open config.yaml: no such file or directory",
+//		reflect.TypeOf(&fs.PathError{}),
+//		reflect.TypeOf(syscall.Errno(0))))
+//
+// The failure message includes every error in the chain with its type.
+func ErrorChain(err error, expected ...interface{}) Comparison {
+	return func() Result {
+		if err == nil {
+			return ResultFailure("error is nil")
+		}
+		chain := flattenErrorChain(err)
+		for i, item := range expected {
+			if i >= len(chain) {
+				return errorChainFailure(err,
+					fmt.Sprintf("error chain has %d errors, expected %d", len(chain), len(expected)))
+			}
+			if msg, ok := matchErrorLayer(chain[i].err, item); !ok {
+				return errorChainFailure(err, fmt.Sprintf("error %d %s", i, msg))
+			}
+		}
+		if len(chain) > len(expected) {
+			return errorChainFailure(err,
+				fmt.Sprintf("error chain has %d errors, expected %d", len(chain), len(expected)))
+		}
+		return ResultSuccess
+	}
+}
+
+func matchErrorLayer(err error, expected interface{}) (string, bool) {
+	switch typed := expected.(type) {
+	case string:
+		if err.Error() == typed {
+			return "", true
+		}
+		return fmt.Sprintf("has message %q, not %q", err.Error(), typed), false
+	case reflect.Type:
+		actual := reflect.TypeOf(err)
+		if actual == typed || typed.Kind() == reflect.Interface && actual.Implements(typed) {
+			return "", true
+		}
+		return fmt.Sprintf("is %s, not %s", actual, typed), false
+	}
+	return fmt.Sprintf("can not be compared to invalid type %T", expected), false
+}
+
+func errorChainFailure(err error, msg string) Result {
+	return ResultFailure(msg + "\n" + formatErrorChain(err))
+}
+
+type errorLayer struct {
+	err   error
+	depth int
+}
+
+// flattenErrorChain returns err and every error it wraps, in depth-first order.
+func flattenErrorChain(err error) []errorLayer {
+	var layers []errorLayer
+	var walk func(err error, depth int)
+	walk = func(err error, depth int) {
+		for err != nil {
+			layers = append(layers, errorLayer{err: err, depth: depth})
+			switch wrapped := err.(type) { //nolint:errorlint,nolintlint // walking the chain
+			case interface{ Unwrap() []error }:
+				for _, item := range wrapped.Unwrap() {
+					walk(item, depth+1)
+				}
+				return
+			case interface{ Unwrap() error }:
+				err = wrapped.Unwrap()
+			default:
+				return
+			}
+		}
+	}
+	walk(err, 0)
+	return layers
+}
+
+// formatErrorChain returns every error in the chain of err, with its type. The
+// errors wrapped by a multi-error are indented.
+func formatErrorChain(err error) string {
+	out := new(strings.Builder)
+	out.WriteString("error chain:")
+	for _, layer := range flattenErrorChain(err) {
+		fmt.Fprintf(out, "\n%s%T: %q", strings.Repeat("\t", layer.depth+1), layer.err, layer.err)
+	}
+	return out.String()
+}
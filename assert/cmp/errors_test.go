@@ -0,0 +1,115 @@
+package cmp
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type multiError []error
+
+func (m multiError) Error() string {
+	return fmt.Sprintf("%d errors", len(m))
+}
+
+func (m multiError) Unwrap() []error {
+	return m
+}
+
+func TestErrorAs(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.yaml", Err: os.ErrNotExist}
+	err := fmt.Errorf("read config: %w", pathErr)
+
+	var target *fs.PathError
+	result := ErrorAs(err, &target)()
+	assertSuccess(t, result)
+	if target != pathErr {
+		t.Fatalf("expected target to be set to %v, got %v", pathErr, target)
+	}
+
+	t.Run("multi-error", func(t *testing.T) {
+		var target *fs.PathError
+		result := ErrorAs(multiError{stubError{}, err}, &target)()
+		assertSuccess(t, result)
+		if target != pathErr {
+			t.Fatalf("expected target to be set to %v, got %v", pathErr, target)
+		}
+	})
+
+	t.Run("interface target", func(t *testing.T) {
+		var target interface{ Temporary() bool }
+		result := ErrorAs(err, &target)()
+		assertFailure(t, result, `no error in the chain is a interface { Temporary() bool }
+error chain:
+	*fmt.wrapError: "read config: open config.yaml: file does not exist"
+	*fs.PathError: "open config.yaml: file does not exist"
+	*errors.errorString: "file does not exist"`)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		var target *fs.PathError
+		result := ErrorAs(nil, &target)()
+		assertFailure(t, result, "error is nil, not *fs.PathError")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		var target *fs.PathError
+		result := ErrorAs(err, target)()
+		assertFailure(t, result, "invalid type for target: *fs.PathError")
+
+		result = ErrorAs(err, new(string))()
+		assertFailure(t, result, "invalid type for target: *string")
+	})
+}
+
+func TestErrorChain(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.yaml", Err: os.ErrNotExist}
+	err := fmt.Errorf("read config: %w", pathErr)
+
+	result := ErrorChain(err,
+		"read config: open config.yaml: file does not exist",
+		reflect.TypeOf(pathErr),
+		reflect.TypeOf((*error)(nil)).Elem())()
+	assertSuccess(t, result)
+
+	t.Run("wrong message", func(t *testing.T) {
+		result := ErrorChain(err, "read config", reflect.TypeOf(pathErr), "file does not exist")()
+		assertFailure(t, result, `error 0 has message "read config: open config.yaml: file does not exist", not "read config"
+error chain:
+	*fmt.wrapError: "read config: open config.yaml: file does not exist"
+	*fs.PathError: "open config.yaml: file does not exist"
+	*errors.errorString: "file does not exist"`)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		result := ErrorChain(err, reflect.TypeOf(pathErr))()
+		assertFailureHasPrefix(t, result, "error 0 is *fmt.wrapError, not *fs.PathError\n")
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		result := ErrorChain(err, reflect.TypeOf(err))()
+		assertFailureHasPrefix(t, result, "error chain has 3 errors, expected 1\n")
+
+		result = ErrorChain(pathErr, reflect.TypeOf(pathErr), "file does not exist", "other")()
+		assertFailureHasPrefix(t, result, "error chain has 2 errors, expected 3\n")
+	})
+
+	t.Run("multi-error", func(t *testing.T) {
+		multi := multiError{stubError{}, err}
+		result := ErrorChain(multi, "2 errors", "stub error", "other")()
+		assertFailure(t, result, `error 2 has message "read config: open config.yaml: file does not exist", not "other"
+error chain:
+	cmp.multiError: "2 errors"
+		cmp.stubError: "stub error"
+		*fmt.wrapError: "read config: open config.yaml: file does not exist"
+		*fs.PathError: "open config.yaml: file does not exist"
+		*errors.errorString: "file does not exist"`)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		result := ErrorChain(nil, "message")()
+		assertFailure(t, result, "error is nil")
+	})
+}
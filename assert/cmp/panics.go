@@ -0,0 +1,113 @@
+package cmp
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// PanicsWithValue succeeds if f() panics with a value that is equal to
+// expected. The values are compared using [reflect.DeepEqual].
+//
+// The failure message includes the value and the stack of the panic.
+func PanicsWithValue(f func(), expected interface{}) Comparison {
+	return func() Result {
+		recovered := recoverPanic(f)
+		switch {
+		case !recovered.panicked:
+			return ResultFailure("did not panic")
+		case reflect.DeepEqual(recovered.value, expected):
+			return ResultSuccess
+		}
+		return ResultFailure(fmt.Sprintf("panic value %v (%T) is not %v (%T)",
+			recovered.value, recovered.value, expected, expected) + recovered.stackTrace())
+	}
+}
+
+// PanicsWithError succeeds if f() panics with an error, and the comparison
+// returned by match succeeds for that error.
+//
+// Example:
+//
+//	assert.Assert(t, cmp.PanicsWithError(f, func(err error) cmp.Comparison {
+//		return cmp.ErrorIs(err, os.ErrNotExist)
+//	}))
+//
+// The failure message includes the value and the stack of the panic.
+func PanicsWithError(f func(), match func(err error) Comparison) Comparison {
+	return func() Result {
+		recovered := recoverPanic(f)
+		if !recovered.panicked {
+			return ResultFailure("did not panic")
+		}
+		err, ok := recovered.value.(error)
+		if !ok {
+			return ResultFailure(fmt.Sprintf("panic value %v (%T) is not an error",
+				recovered.value, recovered.value) + recovered.stackTrace())
+		}
+		result := match(err)()
+		if result.Success() {
+			return ResultSuccess
+		}
+		return ResultFailure(fmt.Sprintf("panic error does not match: %s",
+			resultMessage(result)) + recovered.stackTrace())
+	}
+}
+
+// NotPanics succeeds if f() returns without a panic.
+//
+// The failure message includes the value and the stack of the panic.
+func NotPanics(f func()) Comparison {
+	return func() Result {
+		recovered := recoverPanic(f)
+		if !recovered.panicked {
+			return ResultSuccess
+		}
+		return ResultFailure(fmt.Sprintf("panicked: %v (%T)", recovered.value, recovered.value) +
+			recovered.stackTrace())
+	}
+}
+
+type recoveredPanic struct {
+	panicked bool
+	value    interface{}
+	stack    []byte
+}
+
+func (r recoveredPanic) stackTrace() string {
+	return "\n\npanic stack:\n" + string(r.stack)
+}
+
+// recoverPanic calls f and returns the value and stack of any panic. Unlike
+// recover, a panic with a nil value is reported as a panic. When f calls
+// runtime.Goexit, for example from t.FailNow, recoverPanic does not return and
+// the goroutine exits.
+func recoverPanic(f func()) (result recoveredPanic) {
+	completed := false
+	func() {
+		defer func() {
+			if !completed {
+				result.value = recover()
+				result.stack = debug.Stack()
+			}
+		}()
+		f()
+		completed = true
+	}()
+	// this is only reached when f returns or panics, runtime.Goexit continues
+	// to unwind the stack.
+	result.panicked = !completed
+	return result
+}
+
+// resultMessage returns the failure message of result without the source of
+// the comparison args.
+func resultMessage(result Result) string {
+	switch typed := result.(type) {
+	case StringResult:
+		return typed.FailureMessage()
+	case templatedResult:
+		return typed.FailureMessage(nil)
+	}
+	return fmt.Sprintf("comparison returned invalid Result type: %T", result)
+}
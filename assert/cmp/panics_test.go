@@ -0,0 +1,74 @@
+package cmp
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPanicsWithValue(t *testing.T) {
+	result := PanicsWithValue(func() { panic("boom") }, "boom")()
+	assertSuccess(t, result)
+
+	result = PanicsWithValue(func() { panic([]int{1, 2}) }, []int{1, 2})()
+	assertSuccess(t, result)
+
+	result = PanicsWithValue(func() {}, "boom")()
+	assertFailure(t, result, "did not panic")
+
+	result = PanicsWithValue(func() { panic(3) }, "boom")()
+	assertFailureHasPrefix(t, result, "panic value 3 (int) is not boom (string)\n\npanic stack:\n")
+	assertPanicStack(t, result)
+}
+
+func TestPanicsWithError(t *testing.T) {
+	isNotExist := func(err error) Comparison {
+		return ErrorIs(err, os.ErrNotExist)
+	}
+	result := PanicsWithError(func() { panic(os.ErrNotExist) }, isNotExist)()
+	assertSuccess(t, result)
+
+	result = PanicsWithError(func() {}, isNotExist)()
+	assertFailure(t, result, "did not panic")
+
+	result = PanicsWithError(func() { panic("boom") }, isNotExist)()
+	assertFailureHasPrefix(t, result, "panic value boom (string) is not an error\n\npanic stack:\n")
+
+	result = PanicsWithError(func() { panic(errors.New("other")) }, isNotExist)()
+	assertFailureHasPrefix(t, result,
+		`panic error does not match: error is "other", not "file does not exist"`)
+	assertPanicStack(t, result)
+}
+
+func TestNotPanics(t *testing.T) {
+	result := NotPanics(func() {})()
+	assertSuccess(t, result)
+
+	result = NotPanics(func() { panic("boom") })()
+	assertFailureHasPrefix(t, result, "panicked: boom (string)\n\npanic stack:\n")
+	assertPanicStack(t, result)
+}
+
+func TestNotPanics_Goexit(t *testing.T) {
+	returned := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NotPanics(runtime.Goexit)()
+		returned = true
+	}()
+	<-done
+	if returned {
+		t.Fatal("expected runtime.Goexit to exit the goroutine")
+	}
+}
+
+func assertPanicStack(t *testing.T, result Result) {
+	t.Helper()
+	message := result.(StringResult).FailureMessage()
+	if !strings.Contains(message, "panics_test.go") {
+		t.Errorf("expected panic stack to include the panic, got\n%s", message)
+	}
+}
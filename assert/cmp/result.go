@@ -74,6 +74,21 @@ func (r templatedResult) UpdatedExpected(stackIndex int) error {
 	return source.UpdateExpectedValue(stackIndex+1, r.data["x"], r.data["y"])
 }
 
+// ComparedValues returns the actual and expected values of the comparison,
+// when they are available in the template data.
+func (r templatedResult) ComparedValues() (actual, expected interface{}, ok bool) {
+	actual, okX := r.data["x"]
+	expected, okY := r.data["y"]
+	return actual, expected, okX && okY
+}
+
+// Diff returns the diff from the template data, or an empty string if the
+// failure message does not include a diff.
+func (r templatedResult) Diff() string {
+	diff, _ := r.data["diff"].(string)
+	return diff
+}
+
 // ResultFailureTemplate returns a [Result] with a template string and data which
 // can be used to format a failure message. The template may access data from .Data,
 // the comparison args with the callArg function, and the formatNode function may
@@ -84,7 +99,8 @@ func ResultFailureTemplate(template string, data map[string]interface{}) Result
 
 func renderMessage(result templatedResult, args []ast.Expr) (string, error) {
 	tmpl := template.New("failure").Funcs(template.FuncMap{
-		"formatNode": source.FormatNode,
+		"formatNode":  source.FormatNode,
+		"formatValue": formatValue,
 		"callArg": func(index int) ast.Expr {
 			if index >= len(args) {
 				return nil
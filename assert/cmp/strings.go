@@ -0,0 +1,210 @@
+package cmp
+
+import (
+	"fmt"
+	"strings"
+
+	"gotest.tools/v3/internal/format"
+)
+
+// HasPrefix succeeds if s starts with prefix.
+//
+// If either value is a multi-line string the failure message will include a
+// unified diff of prefix and the start of s.
+func HasPrefix(s, prefix string) Comparison {
+	return func() Result {
+		if strings.HasPrefix(s, prefix) {
+			return ResultSuccess
+		}
+		if !isMultiLineStringCompare(s, prefix) {
+			return ResultFailure(fmt.Sprintf("string %q does not have prefix %q", s, prefix))
+		}
+		diff := format.UnifiedDiff(format.DiffConfig{
+			A:         leadingLines(s, prefix),
+			B:         prefix,
+			Highlight: true,
+		})
+		return stringDiffResult("string does not have prefix", diff)
+	}
+}
+
+// HasSuffix succeeds if s ends with suffix.
+//
+// If either value is a multi-line string the failure message will include a
+// unified diff of suffix and the end of s.
+func HasSuffix(s, suffix string) Comparison {
+	return func() Result {
+		if strings.HasSuffix(s, suffix) {
+			return ResultSuccess
+		}
+		if !isMultiLineStringCompare(s, suffix) {
+			return ResultFailure(fmt.Sprintf("string %q does not have suffix %q", s, suffix))
+		}
+		diff := format.UnifiedDiff(format.DiffConfig{
+			A:         trailingLines(s, suffix),
+			B:         suffix,
+			Highlight: true,
+		})
+		return stringDiffResult("string does not have suffix", diff)
+	}
+}
+
+// EqualFold succeeds if x and y are equal under Unicode case-folding. See
+// [strings.EqualFold].
+//
+// If either value is a multi-line string the failure message will include a
+// unified diff of the lower case form of the two values.
+func EqualFold(x, y string) Comparison {
+	return func() Result {
+		if strings.EqualFold(x, y) {
+			return ResultSuccess
+		}
+		if !isMultiLineStringCompare(x, y) {
+			return ResultFailureTemplate(`
+				{{- printf "%q" .Data.a }}
+				{{- with callArg 0 }} ({{ formatNode . }}){{ end }} != {{ printf "%q" .Data.b }}
+				{{- with callArg 1 }} ({{ formatNode . }}){{ end }} ignoring case`,
+				map[string]interface{}{"a": x, "b": y})
+		}
+		diff := format.UnifiedDiff(format.DiffConfig{
+			A:         strings.ToLower(x),
+			B:         strings.ToLower(y),
+			Highlight: true,
+		})
+		return stringDiffResult("strings are not equal ignoring case", diff)
+	}
+}
+
+// EqualLines succeeds if x and y contain the same lines. Unlike [Equal], line
+// endings are normalized, so "\r\n" is equal to "\n", and a trailing newline
+// at the end of either value is ignored.
+//
+// The failure message always includes a unified diff of the two values, with
+// the changed characters of each line highlighted.
+func EqualLines(x, y string) Comparison {
+	return func() Result {
+		a, b := normalizeLines(x), normalizeLines(y)
+		if a == b {
+			return ResultSuccess
+		}
+		diff := format.UnifiedDiff(format.DiffConfig{A: a, B: b, Highlight: true})
+		return multiLineDiffResult(diff, x, y)
+	}
+}
+
+// ContainsLine succeeds if one of the lines in s is equal to line. Line
+// endings are not part of the comparison.
+//
+// If s is a multi-line string, the failure message will include a diff of
+// line and the line in s that is the closest match.
+func ContainsLine(s, line string) Comparison {
+	return func() Result {
+		lines := strings.Split(normalizeLines(s), "\n")
+		for _, l := range lines {
+			if l == line {
+				return ResultSuccess
+			}
+		}
+		if len(lines) == 1 {
+			return ResultFailure(fmt.Sprintf("string %q does not contain line %q", s, line))
+		}
+
+		msg := fmt.Sprintf("string does not contain line %q", line)
+		index := closestLine(lines, line)
+		if index < 0 {
+			return ResultFailure(msg)
+		}
+		diff := format.UnifiedDiff(format.DiffConfig{
+			A:         lines[index],
+			B:         line,
+			From:      fmt.Sprintf("line %d", index+1),
+			To:        "expected",
+			Highlight: true,
+		})
+		return ResultFailure(msg + "\nclosest match:\n" + diff)
+	}
+}
+
+func stringDiffResult(msg string, diff string) Result {
+	return ResultFailureTemplate(`{{ .Data.msg }}
+--- {{ with callArg 0 }}{{ formatNode . }}{{else}}←{{end}}
++++ {{ with callArg 1 }}{{ formatNode . }}{{else}}→{{end}}
+{{ .Data.diff }}`,
+		map[string]interface{}{"msg": msg, "diff": diff})
+}
+
+// leadingLines returns the lines from the start of s that would need to match
+// prefix. If the last line of prefix is not a complete line, the matching line
+// from s is truncated to the same length.
+func leadingLines(s, prefix string) string {
+	lines := splitLines(s)
+	want := splitLines(prefix)
+	if len(lines) > len(want) {
+		lines = lines[:len(want)]
+	}
+	last := want[len(want)-1]
+	if i := len(lines) - 1; i == len(want)-1 && !strings.HasSuffix(last, "\n") {
+		if runes := []rune(lines[i]); len(runes) > len([]rune(last)) {
+			lines[i] = string(runes[:len([]rune(last))])
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// trailingLines returns the lines from the end of s that would need to match
+// suffix. The first line is truncated to the length of the first line of suffix.
+func trailingLines(s, suffix string) string {
+	lines := splitLines(s)
+	want := splitLines(suffix)
+	if len(lines) > len(want) {
+		lines = lines[len(lines)-len(want):]
+	}
+	if len(lines) == len(want) {
+		if runes, size := []rune(lines[0]), len([]rune(want[0])); len(runes) > size {
+			lines[0] = string(runes[len(runes)-size:])
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// splitLines splits s after each newline. Unlike strings.SplitAfter, the
+// result does not include an empty line when s ends with a newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func normalizeLines(s string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// closestLine returns the index of the line that shares the longest common
+// prefix and suffix with target, or -1 if no line has anything in common.
+func closestLine(lines []string, target string) int {
+	index, best := -1, 0
+	for i, line := range lines {
+		if score := commonPrefixLen(line, target) + commonSuffixLen(line, target); score > best {
+			index, best = i, score
+		}
+	}
+	return index
+}
+
+func commonPrefixLen(a, b string) int {
+	var i int
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+func commonSuffixLen(a, b string) int {
+	var i int
+	for i < len(a) && i < len(b) && a[len(a)-1-i] == b[len(b)-1-i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,115 @@
+package cmp
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestHasPrefix(t *testing.T) {
+	result := HasPrefix("the full message", "the full")()
+	assertSuccess(t, result)
+
+	result = HasPrefix("the full message", "full")()
+	assertFailure(t, result, `string "the full message" does not have prefix "full"`)
+
+	actual := "first line\nsecond line\nthird line\n"
+	expected := `string does not have prefix
+--- actual
++++ prefix
+@@ -1,3 +1,3 @@
+ first line
+-second line
++second lime
+?         ^
+ third
+`
+	args := []ast.Expr{&ast.Ident{Name: "actual"}, &ast.Ident{Name: "prefix"}}
+	result = HasPrefix(actual, "first line\nsecond lime\nthird")()
+	assertFailureTemplate(t, result, args, expected)
+}
+
+func TestHasSuffix(t *testing.T) {
+	result := HasSuffix("the full message", "message")()
+	assertSuccess(t, result)
+
+	result = HasSuffix("the full message", "full")()
+	assertFailure(t, result, `string "the full message" does not have suffix "full"`)
+
+	actual := "first line\nsecond line\nthird line"
+	expected := `string does not have suffix
+--- actual
++++ suffix
+@@ -1,3 +1,3 @@
+ line
+-second line
++second lime
+?         ^
+ third line
+`
+	args := []ast.Expr{&ast.Ident{Name: "actual"}, &ast.Ident{Name: "suffix"}}
+	result = HasSuffix(actual, "line\nsecond lime\nthird line")()
+	assertFailureTemplate(t, result, args, expected)
+}
+
+func TestEqualFold(t *testing.T) {
+	result := EqualFold("Hello World", "hello world")()
+	assertSuccess(t, result)
+
+	args := []ast.Expr{&ast.Ident{Name: "actual"}, &ast.Ident{Name: "expected"}}
+	result = EqualFold("Hello", "word")()
+	assertFailureTemplate(t, result, args,
+		`"Hello" (actual) != "word" (expected) ignoring case`)
+
+	expected := `strings are not equal ignoring case
+--- actual
++++ expected
+@@ -1,2 +1,2 @@
+ hello
+-world
++word
+?   ^
+`
+	result = EqualFold("Hello\nWorld", "hello\nword")()
+	assertFailureTemplate(t, result, args, expected)
+}
+
+func TestEqualLines(t *testing.T) {
+	result := EqualLines("one\r\ntwo\r\nthree\r\n", "one\ntwo\nthree")()
+	assertSuccess(t, result)
+
+	expected := `
+--- actual
++++ expected
+@@ -1,3 +1,3 @@
+ one
+-two
++tw0
+?  ^
+ three
+`
+	args := []ast.Expr{&ast.Ident{Name: "actual"}, &ast.Ident{Name: "expected"}}
+	result = EqualLines("one\r\ntwo\r\nthree\r\n", "one\ntw0\nthree")()
+	assertFailureTemplate(t, result, args, expected)
+}
+
+func TestContainsLine(t *testing.T) {
+	result := ContainsLine("one\ntwo\nthree\n", "two")()
+	assertSuccess(t, result)
+
+	result = ContainsLine("one two three", "two")()
+	assertFailure(t, result, `string "one two three" does not contain line "two"`)
+
+	result = ContainsLine("one\ntwo\nthree\n", "four")()
+	assertFailure(t, result, `string does not contain line "four"`)
+
+	result = ContainsLine("one\ntwo\nthree\n", "thrice")()
+	assertFailure(t, result, `string does not contain line "thrice"
+closest match:
+--- line 3
++++ expected
+@@ -1 +1 @@
+-three
++thrice
+?   ^^
+`)
+}
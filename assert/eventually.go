@@ -0,0 +1,142 @@
+package assert
+
+import (
+	"fmt"
+	"time"
+
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/internal/assert"
+)
+
+// Eventually fails the test if the comparison returned by comparison does not
+// succeed before the timeout. comparison is called to create a new
+// [cmp.Comparison] for every attempt, so that it can compare the latest state.
+// Attempts are made every interval, until an attempt succeeds or the timeout
+// is reached. If interval is not positive, attempts are made every timeout/10.
+//
+// When the timeout is reached the failure message includes the message from
+// the last attempt, the number of attempts, and when that message was first
+// seen.
+//
+// Example:
+//
+//	assert.Eventually(t, func() cmp.Comparison {
+//		return cmp.Equal(server.State(), "ready")
+//	}, 5*time.Second, 50*time.Millisecond)
+//
+// Eventually uses [testing.T.FailNow] to fail the test. Like t.FailNow,
+// Eventually must be called from the goroutine running the test function, not
+// from other goroutines created during the test. See
+// [gotest.tools/v3/poll.WaitOn] for polling with custom checks.
+func Eventually(
+	t TestingT,
+	comparison func() cmp.Comparison,
+	timeout, interval time.Duration,
+	msgAndArgs ...interface{},
+) {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	interval = defaultInterval(interval, timeout)
+	start := time.Now()
+	var lastMessage string
+	var lastChanged time.Duration
+	for attempt := 1; ; attempt++ {
+		elapsed := time.Since(start)
+		result := comparison()()
+		if result.Success() {
+			return
+		}
+		if message := assert.FailureMessage(result); message != lastMessage || attempt == 1 {
+			lastMessage, lastChanged = message, elapsed
+		}
+
+		remaining := timeout - time.Since(start)
+		if remaining <= 0 {
+			msg := fmt.Sprintf(
+				"comparison did not succeed after %s (%d attempts), "+
+					"with the same failure since %s: %s",
+				formatDuration(timeout), attempt, formatDuration(lastChanged), lastMessage)
+			if !assert.Eval(t, assert.ArgsAfterT, failureComparison(msg), msgAndArgs...) {
+				t.FailNow()
+			}
+			return
+		}
+		time.Sleep(minDuration(interval, remaining))
+	}
+}
+
+// Consistently fails the test if the comparison returned by comparison fails
+// at any point during the duration. comparison is called to create a new
+// [cmp.Comparison] for every attempt, so that it can compare the latest state.
+// Attempts are made every interval, until an attempt fails or the duration has
+// passed. If interval is not positive, attempts are made every duration/10.
+//
+// When an attempt fails the failure message includes the message from the
+// comparison, the number of attempts, and how long the comparison succeeded
+// before it failed.
+//
+// Consistently uses [testing.T.FailNow] to fail the test. Like t.FailNow,
+// Consistently must be called from the goroutine running the test function,
+// not from other goroutines created during the test.
+func Consistently(
+	t TestingT,
+	comparison func() cmp.Comparison,
+	duration, interval time.Duration,
+	msgAndArgs ...interface{},
+) {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	interval = defaultInterval(interval, duration)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		elapsed := time.Since(start)
+		result := comparison()()
+		if !result.Success() {
+			msg := fmt.Sprintf(
+				"comparison failed after %s (attempt %d), expected it to succeed for %s: %s",
+				formatDuration(elapsed), attempt, formatDuration(duration),
+				assert.FailureMessage(result))
+			if !assert.Eval(t, assert.ArgsAfterT, failureComparison(msg), msgAndArgs...) {
+				t.FailNow()
+			}
+			return
+		}
+
+		remaining := duration - time.Since(start)
+		if remaining <= 0 {
+			return
+		}
+		time.Sleep(minDuration(interval, remaining))
+	}
+}
+
+// defaultInterval returns interval, or total/10 if interval is not positive, so
+// that a zero interval does not retry the comparison in a busy loop.
+func defaultInterval(interval, total time.Duration) time.Duration {
+	if interval > 0 {
+		return interval
+	}
+	if interval = total / 10; interval < time.Millisecond {
+		return time.Millisecond
+	}
+	return interval
+}
+
+func failureComparison(msg string) cmp.Comparison {
+	return func() cmp.Result {
+		return cmp.ResultFailure(msg)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
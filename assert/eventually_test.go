@@ -0,0 +1,102 @@
+package assert
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestEventually(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Eventually(fakeT, func() cmp.Comparison {
+			count++
+			return cmp.Equal(count, 3)
+		}, time.Second, time.Millisecond)
+		expectSuccess(t, fakeT)
+		Equal(t, count, 3)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Eventually(fakeT, func() cmp.Comparison {
+			count++
+			if count < 3 {
+				return cmp.Equal(count, -1)
+			}
+			return cmp.Equal("not ready", "ready")
+		}, 50*time.Millisecond, 5*time.Millisecond, "waiting for ready")
+
+		if !fakeT.failNowed {
+			t.Fatal("expected FailNow")
+		}
+		Equal(t, len(fakeT.msgs), 1)
+		expected := regexp.MustCompile(`^assertion failed: comparison did not succeed ` +
+			`after 50ms \((\d+) attempts\), with the same failure since \d+ms: ` +
+			`not ready \(string\) != ready \(string\): waiting for ready$`)
+		match := expected.FindStringSubmatch(fakeT.msgs[0])
+		Assert(t, match != nil, fakeT.msgs[0])
+		Equal(t, match[1], strconv.Itoa(count))
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Eventually(fakeT, func() cmp.Comparison {
+			count++
+			return cmp.Equal(count, -1)
+		}, 50*time.Millisecond, 0)
+
+		if !fakeT.failNowed {
+			t.Fatal("expected FailNow")
+		}
+		// attempts are made every 5ms, instead of in a busy loop
+		Assert(t, count > 1 && count <= 20, "count=%d", count)
+	})
+}
+
+func TestConsistently(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Consistently(fakeT, func() cmp.Comparison {
+			count++
+			return cmp.Equal(count < 100, true)
+		}, 20*time.Millisecond, 5*time.Millisecond)
+		expectSuccess(t, fakeT)
+		Assert(t, count > 1)
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Consistently(fakeT, func() cmp.Comparison {
+			count++
+			return cmp.Equal(count, count)
+		}, 50*time.Millisecond, -time.Second)
+		expectSuccess(t, fakeT)
+		Assert(t, count > 1 && count <= 20, "count=%d", count)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var count int
+		Consistently(fakeT, func() cmp.Comparison {
+			count++
+			return cmp.Equal(count < 3, true)
+		}, time.Second, time.Millisecond)
+
+		if !fakeT.failNowed {
+			t.Fatal("expected FailNow")
+		}
+		Equal(t, count, 3)
+		expected := regexp.MustCompile(`^assertion failed: comparison failed after \d+ms ` +
+			`\(attempt 3\), expected it to succeed for 1s: false \(bool\) != true \(bool\)$`)
+		Assert(t, expected.MatchString(fakeT.msgs[0]), fakeT.msgs[0])
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Package fluent provides a chainable API for the comparisons in
+[gotest.tools/v3/assert/cmp].
+
+	fluent.That(t, got).Equals(want)
+	fluent.That(t, items).HasLen(3).Contains("a").Not().Contains("x")
+
+Every assertion in a chain applies to the value passed to [That] or
+[CheckThat]. The failure messages are the same as the messages printed by
+[gotest.tools/v3/assert.Assert], including the source of the arguments.
+
+[That] stops the test at the first failed assertion in the chain, like
+[gotest.tools/v3/assert.Assert]. [CheckThat] marks the test as failed and
+continues with the rest of the chain, like [gotest.tools/v3/assert.Check].
+*/
+package fluent // import "gotest.tools/v3/assert/fluent"
+
+import (
+	"fmt"
+	"go/ast"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	internalassert "gotest.tools/v3/internal/assert"
+	"gotest.tools/v3/internal/source"
+)
+
+type helperT interface {
+	Helper()
+}
+
+// Value is the actual value of a chain of assertions. Each assertion method
+// returns the Value, so that another assertion can be added to the chain.
+type Value struct {
+	t       assert.TestingT
+	actual  interface{}
+	failNow bool
+	negate  bool
+	// step is the number of methods called on the Value, which is used to find
+	// the source of the arguments to the method in the chain.
+	step int
+}
+
+// That returns a Value for making assertions about actual. If an assertion
+// fails the test is stopped with t.FailNow. Like t.FailNow, the assertions must
+// be called from the goroutine running the test function.
+func That(t assert.TestingT, actual interface{}) *Value {
+	return &Value{t: t, actual: actual, failNow: true}
+}
+
+// CheckThat returns a Value for making assertions about actual. If an
+// assertion fails the test is marked as failed with t.Fail, and the rest of
+// the chain is still checked.
+func CheckThat(t assert.TestingT, actual interface{}) *Value {
+	return &Value{t: t, actual: actual}
+}
+
+// Not negates the next assertion in the chain.
+func (v *Value) Not() *Value {
+	v.step++
+	v.negate = !v.negate
+	return v
+}
+
+// Equals asserts the value is equal to expected using the == operator. See
+// [cmp.Equal].
+func (v *Value) Equals(expected interface{}) *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison, selector := v.next("equal", expected, cmp.Equal(v.actual, expected), false)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+// DeepEquals asserts the value is equal to expected using go-cmp. See
+// [cmp.DeepEqual].
+func (v *Value) DeepEquals(expected interface{}, opts ...gocmp.Option) *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison, selector := v.next("deeply equal", expected,
+		cmp.DeepEqual(v.actual, expected, opts...), false)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+// Contains asserts the value contains item. See [cmp.Contains].
+func (v *Value) Contains(item interface{}) *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison, selector := v.next("contain", item, cmp.Contains(v.actual, item), false)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+// HasLen asserts the value has the expected length. See [cmp.Len].
+func (v *Value) HasLen(expected int) *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison, selector := v.next("have length", expected, cmp.Len(v.actual, expected), false)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+// IsNil asserts the value is nil. See [cmp.Nil].
+func (v *Value) IsNil() *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison, selector := v.next("be nil", noArg, cmp.Nil(v.actual), false)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+// Matches asserts the value is a string that matches the regular expression
+// re. See [cmp.Regexp].
+func (v *Value) Matches(re cmp.RegexOrPattern) *Value {
+	if ht, ok := v.t.(helperT); ok {
+		ht.Helper()
+	}
+	comparison := func() cmp.Result {
+		s, ok := v.actual.(string)
+		if !ok {
+			return cmp.ResultFailure(fmt.Sprintf("value %v (%T) is not a string", v.actual, v.actual))
+		}
+		return cmp.Regexp(re, s)()
+	}
+	comparison, selector := v.next("match", re, comparison, true)
+	if !internalassert.Eval(v.t, selector, comparison) {
+		v.fail()
+	}
+	return v
+}
+
+func (v *Value) fail() {
+	if v.failNow {
+		v.t.FailNow()
+		return
+	}
+	v.t.Fail()
+}
+
+// noArg is used as the argument of assertions that do not have an argument.
+var noArg = struct{}{}
+
+// next returns the comparison for the next step in the chain, and a selector
+// for the source of the arguments to the comparison. If the step is negated
+// the comparison succeeds when the original comparison fails. argsReversed is
+// true when the value is the second argument of the comparison, instead of
+// the first.
+func (v *Value) next(
+	verb string,
+	arg interface{},
+	comparison cmp.Comparison,
+	argsReversed bool,
+) (cmp.Comparison, func([]ast.Expr) []ast.Expr) {
+	v.step++
+	step, negate := v.step, v.negate
+	v.negate = false
+
+	selector := func([]ast.Expr) []ast.Expr {
+		const stackIndex = 4 // Equals/Contains/..., Eval, runComparison, selector
+		actual, args := chainArgs(stackIndex, step)
+		if argsReversed && !negate {
+			return append(args, actual)
+		}
+		return append([]ast.Expr{actual}, args...)
+	}
+	if !negate {
+		return comparison, selector
+	}
+
+	return func() cmp.Result {
+		if !comparison().Success() {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailureTemplate(`
+			{{- formatValue .Data.actual }}{{ with callArg 0 }} ({{ formatNode . }}){{ end }}
+			{{- " should not " }}{{ .Data.verb }}
+			{{- if .Data.hasArg }} {{ formatValue .Data.arg }}
+				{{- with callArg 1 }} ({{ formatNode . }}){{ end }}
+			{{- end }}`,
+			map[string]interface{}{
+				"actual": v.actual,
+				"verb":   verb,
+				"arg":    arg,
+				"hasArg": arg != noArg,
+			})
+	}, selector
+}
+
+// chainArgs returns the source of the argument to That, and the source of the
+// arguments to the method at step in the chain of method calls at stackIndex.
+func chainArgs(stackIndex int, step int) (ast.Expr, []ast.Expr) {
+	expr, err := source.CallExpr(stackIndex + 1)
+	if err != nil {
+		return nil, nil
+	}
+
+	// calls in the chain, starting with the call to That
+	var calls []*ast.CallExpr
+	for {
+		calls = append([]*ast.CallExpr{expr}, calls...)
+		selector, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		inner, ok := selector.X.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		expr = inner
+	}
+
+	if len(calls) <= step || len(calls[0].Args) != 2 {
+		// The chain did not start on this line, so the source of the value
+		// is not available.
+		return nil, nil
+	}
+	return calls[0].Args[1], calls[step].Args
+}
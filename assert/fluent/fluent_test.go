@@ -0,0 +1,110 @@
+package fluent
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type fakeTestingT struct {
+	failNowed bool
+	failed    bool
+	msgs      []string
+}
+
+func (f *fakeTestingT) FailNow() {
+	f.failNowed = true
+}
+
+func (f *fakeTestingT) Fail() {
+	f.failed = true
+}
+
+func (f *fakeTestingT) Log(args ...interface{}) {
+	f.msgs = append(f.msgs, fmt.Sprint(args...))
+}
+
+func (f *fakeTestingT) Helper() {}
+
+// cents is a type with a formatter registered by init.
+type cents int
+
+func init() {
+	assert.RegisterFormatter(func(c cents) string {
+		return fmt.Sprintf("$%d.%02d", c/100, c%100)
+	})
+}
+
+func TestThat_Success(t *testing.T) {
+	fakeT := &fakeTestingT{}
+	items := []string{"a", "b"}
+	That(fakeT, items).HasLen(2).Contains("a").Not().Contains("x").DeepEquals([]string{"a", "b"})
+	That(fakeT, "version 1.2").Matches(`^version \d`).Not().Equals("other")
+	var err error
+	That(fakeT, err).IsNil()
+
+	assert.Assert(t, !fakeT.failed && !fakeT.failNowed)
+	assert.Equal(t, len(fakeT.msgs), 0)
+}
+
+func TestThat_Failures(t *testing.T) {
+	got, want := 3, 4
+	items := []string{"a", "x"}
+
+	t.Run("equals", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		That(fakeT, got).Equals(want)
+		assert.Assert(t, fakeT.failNowed)
+		assert.DeepEqual(t, fakeT.msgs, []string{"assertion failed: 3 (got int) != 4 (want int)"})
+	})
+
+	t.Run("later step in the chain", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		That(fakeT, got).Not().Equals(want).Equals(want)
+		assert.DeepEqual(t, fakeT.msgs, []string{"assertion failed: 3 (got int) != 4 (want int)"})
+	})
+
+	t.Run("negated", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		That(fakeT, items).HasLen(2).Not().Contains("x")
+		assert.DeepEqual(t, fakeT.msgs,
+			[]string{"assertion failed: [a x] (items) should not contain x"})
+	})
+
+	t.Run("negated uses formatter", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		price, other := cents(150), cents(150)
+		That(fakeT, price).Not().Equals(other)
+		assert.DeepEqual(t, fakeT.msgs,
+			[]string{"assertion failed: $1.50 (price) should not equal $1.50 (other)"})
+	})
+
+	t.Run("negated without arg", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		var err error
+		That(fakeT, err).Not().IsNil()
+		assert.DeepEqual(t, fakeT.msgs,
+			[]string{"assertion failed: <nil> (err) should not be nil"})
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		That(fakeT, got).Matches("^[a-z]+$")
+		assert.DeepEqual(t, fakeT.msgs,
+			[]string{"assertion failed: value 3 (int) is not a string"})
+	})
+}
+
+func TestCheckThat(t *testing.T) {
+	fakeT := &fakeTestingT{}
+	items := []string{"a"}
+	CheckThat(fakeT, items).HasLen(2).Contains("b").Contains("a")
+
+	assert.Assert(t, fakeT.failed)
+	assert.Assert(t, !fakeT.failNowed)
+	assert.DeepEqual(t, fakeT.msgs, []string{
+		"assertion failed: expected [a] (length 1) to have length 2",
+		"assertion failed: [a] does not contain b",
+	})
+}
@@ -0,0 +1,127 @@
+package assert
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// GoroutineLeakSettings are used to configure the behaviour of
+// [NoGoroutineLeak].
+type GoroutineLeakSettings struct {
+	// GracePeriod is the amount of time to wait for new goroutines to exit
+	// after the test has finished. Defaults to 1 second.
+	GracePeriod time.Duration
+}
+
+// GoroutineLeakOp is a function which accepts and modifies
+// GoroutineLeakSettings.
+type GoroutineLeakOp func(settings *GoroutineLeakSettings)
+
+// WithGracePeriod sets the amount of time to wait for goroutines to exit.
+func WithGracePeriod(period time.Duration) GoroutineLeakOp {
+	return func(settings *GoroutineLeakSettings) {
+		settings.GracePeriod = period
+	}
+}
+
+// NoGoroutineLeak records the goroutines that are running when it is called,
+// and fails the test when the test finishes if there are any new goroutines
+// still running after the grace period. The failure message includes the stack
+// of each goroutine that was leaked.
+//
+// NoGoroutineLeak should be called at the start of the test. It requires t to
+// have a Cleanup method, like [testing.T]. The check is run as a cleanup
+// function, so it runs after the test and any of its subtests are finished.
+//
+// Goroutines created by other tests are also reported, so NoGoroutineLeak
+// should not be used by tests that call t.Parallel, or in packages with
+// parallel tests.
+func NoGoroutineLeak(t TestingT, ops ...GoroutineLeakOp) {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	settings := &GoroutineLeakSettings{GracePeriod: time.Second}
+	for _, op := range ops {
+		op(settings)
+	}
+	ct, ok := t.(cleanupT)
+	if !ok {
+		t.Log("NoGoroutineLeak requires a testing.T with a Cleanup method")
+		t.FailNow()
+		return
+	}
+
+	before := make(map[string]bool)
+	for _, g := range runningGoroutines() {
+		before[g.id] = true
+	}
+
+	ct.Cleanup(func() {
+		leaked := newGoroutines(before, settings.GracePeriod)
+		if len(leaked) == 0 {
+			return
+		}
+		msg := new(strings.Builder)
+		msg.WriteString("assertion failed: goroutines are still running after the test:\n")
+		for _, g := range leaked {
+			msg.WriteString("\n" + g.stack + "\n")
+		}
+		t.Log(msg.String())
+		t.Fail()
+	})
+}
+
+type goroutine struct {
+	id    string
+	stack string
+}
+
+// newGoroutines waits up to gracePeriod for all the goroutines that are not in
+// before to exit. It returns the goroutines that are still running.
+func newGoroutines(before map[string]bool, gracePeriod time.Duration) []goroutine {
+	deadline := time.Now().Add(gracePeriod)
+	delay := time.Millisecond
+	for {
+		var leaked []goroutine
+		for _, g := range runningGoroutines() {
+			if !before[g.id] {
+				leaked = append(leaked, g)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(delay)
+		if delay < 100*time.Millisecond {
+			delay *= 2
+		}
+	}
+}
+
+// runningGoroutines returns all goroutines except the current goroutine.
+func runningGoroutines() []goroutine {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	// the first goroutine is always the current goroutine
+	stacks := bytes.Split(buf, []byte("\n\n"))[1:]
+	result := make([]goroutine, 0, len(stacks))
+	for _, stack := range stacks {
+		// the first line is the header, ex: goroutine 7 [chan receive]:
+		fields := strings.Fields(string(stack))
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		result = append(result, goroutine{id: fields[1], stack: string(stack)})
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCleanupT struct {
+	fakeTestingT
+	cleanups []func()
+}
+
+func (f *fakeCleanupT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeCleanupT) runCleanups() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestNoGoroutineLeak(t *testing.T) {
+	t.Run("goroutine exits during grace period", func(t *testing.T) {
+		fakeT := &fakeCleanupT{}
+		NoGoroutineLeak(fakeT)
+
+		done := make(chan struct{})
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(done)
+		}()
+		fakeT.runCleanups()
+		expectSuccess(t, &fakeT.fakeTestingT)
+	})
+
+	t.Run("goroutine leaked", func(t *testing.T) {
+		fakeT := &fakeCleanupT{}
+		NoGoroutineLeak(fakeT, WithGracePeriod(20*time.Millisecond))
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go leakyGoroutine(stop)
+		fakeT.runCleanups()
+
+		if !fakeT.failed {
+			t.Fatal("expected the test to fail")
+		}
+		Equal(t, len(fakeT.msgs), 1)
+		msg := fakeT.msgs[0]
+		Assert(t, strings.HasPrefix(msg,
+			"assertion failed: goroutines are still running after the test:\n"), msg)
+		Assert(t, strings.Contains(msg, "assert.leakyGoroutine"), msg)
+	})
+
+	t.Run("without cleanup", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		NoGoroutineLeak(fakeT)
+		expectFailNowed(t, fakeT, "NoGoroutineLeak requires a testing.T with a Cleanup method")
+	})
+}
+
+func leakyGoroutine(stop chan struct{}) {
+	<-stop
+}
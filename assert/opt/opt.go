@@ -5,10 +5,14 @@ package opt // import "gotest.tools/v3/assert/opt"
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
+	"unsafe"
 
 	gocmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gotest.tools/v3/assert/cmp"
 )
 
 // DurationWithThreshold returns a [gocmp.Comparer] for comparing [time.Duration]. The
@@ -116,3 +120,241 @@ func isStructField(step gocmp.PathStep, name string) bool {
 	field, ok := step.(gocmp.StructField)
 	return ok && field.Name() == name
 }
+
+// Fields is a [gocmp.FilterPath] filter that matches the struct fields selected
+// by selector. Unlike [PathString] and [PathField], the fields are selected
+// with Go code, so a typo is a compile error instead of a filter that silently
+// matches nothing.
+//
+// selector must be a function with the signature
+//
+//	func(t *T) interface{}
+//
+// where T is a struct type. selector must return a pointer to a field of t, or
+// a []interface{} of pointers to fields. Nested fields, including fields of
+// structs referenced by a pointer, may be selected. The filter matches the
+// fields when they are part of a value of type T. Fields panics if selector
+// does not have the expected signature, or does not return a pointer to a
+// field.
+//
+//	opt.Fields(func(t *Request) interface{} { return &t.Meta.Label })
+func Fields(selector interface{}) func(gocmp.Path) bool {
+	fn := reflect.ValueOf(selector)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 1 || fnType.NumOut() != 1 ||
+		!isPtrToStruct(fnType.In(0)) || fnType.Out(0) != emptyInterfaceType {
+		panic(fmt.Sprintf("invalid selector %s, must be func(*T) interface{}", fnType))
+	}
+	structType := fnType.In(0).Elem()
+
+	root := reflect.New(structType)
+	allocNestedStructs(root.Elem(), map[reflect.Type]bool{structType: true})
+	selected := fn.Call([]reflect.Value{root})[0].Interface()
+
+	var pointers []interface{}
+	switch typed := selected.(type) {
+	case []interface{}:
+		pointers = typed
+	default:
+		pointers = []interface{}{typed}
+	}
+
+	paths := make([][]string, 0, len(pointers))
+	for _, ptr := range pointers {
+		value := reflect.ValueOf(ptr)
+		if value.Kind() != reflect.Ptr || value.IsNil() {
+			panic(fmt.Sprintf("selector must return a pointer to a field of %s, got %T",
+				structType, ptr))
+		}
+		path := findFieldPath(root.Elem(), value.Pointer(), value.Type().Elem())
+		if path == nil {
+			panic(fmt.Sprintf("selector returned a pointer that is not a field of %s", structType))
+		}
+		paths = append(paths, path)
+	}
+
+	return func(path gocmp.Path) bool {
+		for _, names := range paths {
+			if matchFieldPath(path, structType, names) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+func isPtrToStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct
+}
+
+// allocNestedStructs sets every pointer to a struct in value to a new value, so
+// that a selector can access nested fields without a nil pointer dereference.
+// Unexported fields are set using unsafe, because a selector in the package of
+// the struct may use them. value must be addressable. seen prevents infinite
+// recursion on recursive types.
+func allocNestedStructs(value reflect.Value, seen map[reflect.Type]bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if !field.CanSet() {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+		switch {
+		case field.Kind() == reflect.Struct:
+			allocNestedStructs(field, seen)
+		case isPtrToStruct(field.Type()) && !seen[field.Type().Elem()]:
+			field.Set(reflect.New(field.Type().Elem()))
+			seen[field.Type().Elem()] = true
+			allocNestedStructs(field.Elem(), seen)
+			delete(seen, field.Type().Elem())
+		}
+	}
+}
+
+// findFieldPath returns the names of the fields from value to the field at addr
+// with type typ.
+func findFieldPath(value reflect.Value, addr uintptr, typ reflect.Type) []string {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		name := value.Type().Field(i).Name
+		if field.UnsafeAddr() == addr && field.Type() == typ {
+			return []string{name}
+		}
+
+		switch {
+		case field.Kind() == reflect.Struct:
+		case isPtrToStruct(field.Type()) && !field.IsNil():
+			field = field.Elem()
+		default:
+			continue
+		}
+		if path := findFieldPath(field, addr, typ); path != nil {
+			return append([]string{name}, path...)
+		}
+	}
+	return nil
+}
+
+// matchFieldPath returns true if path ends with the fields in names, starting
+// from a value of type structType.
+func matchFieldPath(path gocmp.Path, structType reflect.Type, names []string) bool {
+	i := len(path) - 1
+	for k := len(names) - 1; k >= 0; k-- {
+		if k < len(names)-1 {
+			// skip the pointer indirection between nested fields
+			for i > 0 && isIndirect(path.Index(i)) {
+				i--
+			}
+		}
+		if i < 1 || !isStructField(path.Index(i), names[k]) {
+			return false
+		}
+		i--
+	}
+	return path.Index(i).Type() == structType
+}
+
+func isIndirect(step gocmp.PathStep) bool {
+	_, ok := step.(gocmp.Indirect)
+	return ok
+}
+
+// IgnoreZeroFields returns a [gocmp.Option] that ignores struct fields which
+// have the zero value in y. When y is the expected value, IgnoreZeroFields
+// can be used to compare only the fields that are set in the expected value.
+func IgnoreZeroFields() gocmp.Option {
+	return gocmp.FilterPath(func(path gocmp.Path) bool {
+		field, ok := path.Last().(gocmp.StructField)
+		if !ok {
+			return false
+		}
+		_, y := field.Values()
+		return y.IsValid() && y.IsZero()
+	}, gocmp.Ignore())
+}
+
+// SortSlicesByKey returns a [gocmp.Option] that sorts slices before they are
+// compared, so that the order of the elements is not part of the comparison.
+//
+// key must be a function with the signature
+//
+//	func(T) K
+//
+// where T is the element type of the slices, and K is a string, integer, or
+// float type. Elements are sorted by the value returned by key. SortSlicesByKey
+// panics if key does not have the expected signature.
+//
+//	opt.SortSlicesByKey(func(u User) string { return u.Name })
+func SortSlicesByKey(key interface{}) gocmp.Option {
+	fn := reflect.ValueOf(key)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 1 || fnType.NumOut() != 1 ||
+		!isOrderedKind(fnType.Out(0).Kind()) {
+		panic(fmt.Sprintf("invalid key %s, must be func(T) K where K is ordered", fnType))
+	}
+	elemType := fnType.In(0)
+	lessType := reflect.FuncOf(
+		[]reflect.Type{elemType, elemType}, []reflect.Type{reflect.TypeOf(true)}, false)
+	less := reflect.MakeFunc(lessType, func(args []reflect.Value) []reflect.Value {
+		x := fn.Call(args[:1])[0]
+		y := fn.Call(args[1:])[0]
+		return []reflect.Value{reflect.ValueOf(lessOrdered(x, y))}
+	})
+	return cmpopts.SortSlices(less.Interface())
+}
+
+func isOrderedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+func lessOrdered(x, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() < y.Int()
+	case reflect.Float32, reflect.Float64:
+		return x.Float() < y.Float()
+	case reflect.String:
+		return x.String() < y.String()
+	}
+	return x.Uint() < y.Uint()
+}
+
+// NilEqualsEmpty returns a [gocmp.Option] that treats nil and empty maps as
+// equal, and nil and empty slices as equal.
+func NilEqualsEmpty() gocmp.Option {
+	return cmpopts.EquateEmpty()
+}
+
+// DocumentPath is a [cmp.DocumentPathFilter] that returns true when the path
+// matches any of the specs. The specs use the same format as the path, and
+// may use [*] to match any array index, and .* to match any object key.
+//
+//	assert.Assert(t, cmp.JSONEqual(actual, expected, opt.DocumentPath("$.items[*].id")))
+func DocumentPath(specs ...string) cmp.DocumentPathFilter {
+	patterns := make([]*regexp.Regexp, 0, len(specs))
+	for _, spec := range specs {
+		patterns = append(patterns, documentPathPattern(spec))
+	}
+	return func(path string) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(path) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func documentPathPattern(spec string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(spec)
+	pattern = strings.ReplaceAll(pattern, `\[\*\]`, `\[\d+\]`)
+	pattern = strings.ReplaceAll(pattern, `\.\*`, `(\.[^.\[]+|\["(?:[^"\\]|\\.)*"\])`)
+	return regexp.MustCompile("^" + pattern + "$")
+}
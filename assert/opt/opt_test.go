@@ -7,6 +7,7 @@ import (
 
 	gocmp "github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
 )
 
 func TestDurationWithThreshold(t *testing.T) {
@@ -263,3 +264,146 @@ func TestPathDebug(t *testing.T) {
 	}
 	assert.Check(t, gocmp.Equal(fixture, fixture, gocmp.FilterPath(PathDebug, gocmp.Ignore())))
 }
+
+func TestDocumentPath(t *testing.T) {
+	filter := DocumentPath("$.id", "$.items[*].meta.*", `$["content-type"]`)
+
+	var testcases = []struct {
+		path     string
+		expected bool
+	}{
+		{path: "$.id", expected: true},
+		{path: "$.ids"},
+		{path: "$.items[0].meta.created", expected: true},
+		{path: `$.items[12].meta["created-at"]`, expected: true},
+		{path: "$.items[0].meta"},
+		{path: "$.items[0].meta.created.at"},
+		{path: `$["content-type"]`, expected: true},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, filter(tc.path), tc.expected, tc.path)
+	}
+}
+
+func TestDocumentPathWithJSONEqual(t *testing.T) {
+	x := `{"items": [{"id": "a1", "name": "one"}, {"id": "b2", "name": "two"}]}`
+	y := `{"items": [{"id": "c3", "name": "one"}, {"id": "d4", "name": "two"}]}`
+	assert.Assert(t, cmp.JSONEqual(x, y, DocumentPath("$.items[*].id")))
+}
+
+type request struct {
+	URL  string
+	Meta requestMeta
+	Ref  *request
+	Info *requestInfo
+}
+
+type requestMeta struct {
+	Label string
+	Count int
+}
+
+type requestInfo struct {
+	ID string
+}
+
+func TestFields(t *testing.T) {
+	fixture := request{
+		Meta: requestMeta{Label: "a"},
+		Ref:  &request{Meta: requestMeta{Label: "b"}},
+		Info: &requestInfo{ID: "c"},
+	}
+
+	filter := Fields(func(r *request) interface{} { return &r.Meta.Label })
+	matches := matchPaths(fixture, filter)
+	expected := []string{
+		"{opt.request}.Meta.Label",
+		"{opt.request}.Ref.Meta.Label",
+	}
+	assert.DeepEqual(t, uniqueStrings(matches), expected)
+
+	filter = Fields(func(r *request) interface{} {
+		return []interface{}{&r.URL, &r.Info.ID}
+	})
+	matches = matchPaths(fixture, filter)
+	expected = []string{
+		"{opt.request}.Info.ID",
+		"{opt.request}.Ref.URL",
+		"{opt.request}.URL",
+	}
+	assert.DeepEqual(t, uniqueStrings(matches), expected)
+
+	t.Run("with DeepEqual", func(t *testing.T) {
+		x := request{URL: "a", Meta: requestMeta{Label: "x", Count: 1}}
+		y := request{URL: "a", Meta: requestMeta{Label: "y", Count: 1}}
+		ignoreLabel := gocmp.FilterPath(
+			Fields(func(r *request) interface{} { return &r.Meta.Label }), gocmp.Ignore())
+		assert.DeepEqual(t, x, y, ignoreLabel)
+	})
+
+	t.Run("unexported pointer field", func(t *testing.T) {
+		type private struct {
+			URL  string
+			info *requestInfo
+		}
+		x := private{URL: "a", info: &requestInfo{ID: "x"}}
+		y := private{URL: "a", info: &requestInfo{ID: "y"}}
+		ignoreID := gocmp.FilterPath(
+			Fields(func(r *private) interface{} { return &r.info.ID }), gocmp.Ignore())
+		assert.DeepEqual(t, x, y, ignoreID, gocmp.AllowUnexported(private{}))
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		assert.Assert(t, cmp.Panics(func() { Fields(func(r request) interface{} { return nil }) }))
+		assert.Assert(t, cmp.PanicsWithValue(
+			func() { Fields(func(r *request) interface{} { return r.URL }) },
+			"selector must return a pointer to a field of opt.request, got string"))
+		assert.Assert(t, cmp.PanicsWithValue(
+			func() { Fields(func(r *request) interface{} { return new(string) }) },
+			"selector returned a pointer that is not a field of opt.request"))
+	})
+}
+
+// uniqueStrings removes repeated items from a sorted slice.
+func uniqueStrings(items []string) []string {
+	var result []string
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func TestIgnoreZeroFields(t *testing.T) {
+	actual := request{URL: "http://example.com", Meta: requestMeta{Label: "a", Count: 3}}
+	expected := request{Meta: requestMeta{Count: 3}}
+	assert.DeepEqual(t, actual, expected, IgnoreZeroFields())
+
+	expected.Meta.Label = "b"
+	assert.Assert(t, !gocmp.Equal(actual, expected, IgnoreZeroFields()))
+}
+
+func TestDeepEqualTimeIgnoresMonotonicClockAndLocation(t *testing.T) {
+	now := time.Now()
+	utc := now.UTC().Round(0)
+	assert.Assert(t, now != utc)
+	assert.DeepEqual(t, now, utc)
+}
+
+func TestSortSlicesByKey(t *testing.T) {
+	x := []requestMeta{{Label: "b", Count: 1}, {Label: "a", Count: 2}}
+	y := []requestMeta{{Label: "a", Count: 2}, {Label: "b", Count: 1}}
+	assert.DeepEqual(t, x, y, SortSlicesByKey(func(m requestMeta) string { return m.Label }))
+	assert.DeepEqual(t, x, y, SortSlicesByKey(func(m requestMeta) int { return m.Count }))
+
+	assert.Assert(t, cmp.PanicsWithValue(
+		func() { SortSlicesByKey(func(m requestMeta) requestInfo { return requestInfo{} }) },
+		"invalid key func(opt.requestMeta) opt.requestInfo, must be func(T) K where K is ordered"))
+}
+
+func TestNilEqualsEmpty(t *testing.T) {
+	x := request{}
+	assert.DeepEqual(t, map[string][]int{"a": nil}, map[string][]int{"a": {}}, NilEqualsEmpty())
+	assert.DeepEqual(t, x, x, NilEqualsEmpty())
+}
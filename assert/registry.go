@@ -0,0 +1,61 @@
+package assert
+
+import (
+	"gotest.tools/v3/internal/registry"
+)
+
+// RegisterComparer registers compare as the comparer for values of type T,
+// for every test in the test binary. compare must be a function with the
+// signature
+//
+//	func(x, y T) bool
+//
+// The comparer is used by [Equal] and [cmp.Equal] in place of the == operator
+// when both values are assignable to T, by [DeepEqual] and [cmp.DeepEqual] for
+// values of type T at any depth, and by [cmp.Contains] for the elements of a
+// slice or array. T may be an interface type, in which case the comparer
+// applies to all the types that implement T. A comparer for T replaces any
+// comparer previously registered for T.
+//
+// Like [gocmp.Comparer], the comparer must be symmetric and deterministic.
+// [DeepEqual] panics, and [Equal] and [cmp.Contains] fail, if more than one
+// comparer applies to the same values, for example a comparer for a type and
+// a comparer for an interface implemented by the type. Comparers are usually
+// registered from an init function or TestMain.
+//
+// RegisterComparer panics if compare does not have the expected signature.
+//
+// Example:
+//
+//	func init() {
+//		assert.RegisterComparer(func(x, y *big.Int) bool {
+//			return x.Cmp(y) == 0
+//		})
+//	}
+func RegisterComparer(compare interface{}) {
+	if err := registry.RegisterComparer(compare); err != nil {
+		panic(err.Error())
+	}
+}
+
+// RegisterFormatter registers format as the formatter for values of type T, for
+// every test in the test binary. format must be a function with the signature
+//
+//	func(v T) string
+//
+// The formatter is used to print values of type T in the failure messages of
+// [Equal], [cmp.Equal], and [cmp.Contains]. T may be an interface type, in
+// which case the formatter is used for the types that implement T and do not
+// have a formatter of their own. A formatter for T replaces any formatter
+// previously registered for T.
+//
+// Formatters are not used by [DeepEqual] and [cmp.DeepEqual], because their
+// failure message is the diff produced by go-cmp, which prints the fields of
+// the values.
+//
+// RegisterFormatter panics if format does not have the expected signature.
+func RegisterFormatter(format interface{}) {
+	if err := registry.RegisterFormatter(format); err != nil {
+		panic(err.Error())
+	}
+}
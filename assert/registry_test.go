@@ -0,0 +1,127 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert/cmp"
+)
+
+// decimal is a type with values that are equal even when the fields are not.
+type decimal struct {
+	unscaled int
+	scale    int
+}
+
+func (d decimal) normalize() decimal {
+	for d.scale > 0 && d.unscaled%10 == 0 {
+		d.unscaled /= 10
+		d.scale--
+	}
+	return d
+}
+
+type account struct {
+	Name    string
+	Balance decimal
+}
+
+func init() {
+	RegisterComparer(func(x, y decimal) bool {
+		return x.normalize() == y.normalize()
+	})
+	RegisterFormatter(func(d decimal) string {
+		s := fmt.Sprintf("%0*d", d.scale+1, d.unscaled)
+		if d.scale == 0 {
+			return s
+		}
+		return s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	})
+}
+
+func TestRegisterComparer(t *testing.T) {
+	oneTen := decimal{unscaled: 110, scale: 2}
+	oneTenShort := decimal{unscaled: 11, scale: 1}
+
+	t.Run("success", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Equal(fakeT, oneTen, oneTenShort)
+		DeepEqual(fakeT, account{Balance: oneTen}, account{Balance: oneTenShort})
+		DeepEqual(fakeT, []decimal{oneTen}, []decimal{oneTenShort})
+		Check(fakeT, cmp.Contains([]decimal{{unscaled: 2}, oneTen}, oneTenShort))
+		expectSuccess(t, fakeT)
+	})
+
+	t.Run("Equal failure uses formatter", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		fiveCents := decimal{unscaled: 5, scale: 2}
+		Equal(fakeT, oneTen, fiveCents)
+		expectFailNowed(t, fakeT,
+			"assertion failed: 1.10 (oneTen assert.decimal) != 0.05 (fiveCents assert.decimal)")
+	})
+
+	t.Run("DeepEqual failure", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		DeepEqual(fakeT, account{Balance: oneTen}, account{Balance: decimal{unscaled: 5}})
+		Assert(t, fakeT.failNowed)
+	})
+
+	t.Run("Contains failure uses formatter", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Check(fakeT, cmp.Contains([]decimal{oneTen}, decimal{unscaled: 3}))
+		Equal(t, strings.Join(fakeT.msgs, "\n"), "assertion failed: [1.10] does not contain 3")
+	})
+}
+
+// identifier is an interface with a comparer registered by init, to test that
+// comparers for interface types apply to every type that implements them.
+type identifier interface {
+	ID() string
+}
+
+type userID string
+
+func (u userID) ID() string { return strings.ToLower(string(u)) }
+
+type groupID string
+
+func (g groupID) ID() string { return strings.ToLower(string(g)) }
+
+func init() {
+	RegisterComparer(func(x, y identifier) bool {
+		return x.ID() == y.ID()
+	})
+}
+
+func TestRegisterComparer_Interface(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Equal(fakeT, userID("Admin"), userID("admin"))
+		Equal(fakeT, userID("admin"), groupID("ADMIN"))
+		Check(fakeT, cmp.Contains([]userID{"root", "Admin"}, userID("admin")))
+		DeepEqual(fakeT, []identifier{userID("Admin")}, []identifier{groupID("admin")})
+		expectSuccess(t, fakeT)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Equal(fakeT, userID("admin"), groupID("root"))
+		expectFailNowed(t, fakeT,
+			"assertion failed: admin (assert.userID) != root (assert.groupID)")
+	})
+}
+
+func TestRegisterComparer_InvalidSignature(t *testing.T) {
+	defer func() {
+		Equal(t, recover(), "invalid comparer func(int, string) bool, must be func(x, y T) bool")
+	}()
+	RegisterComparer(func(int, string) bool { return false })
+}
+
+func TestRegisterFormatter_InvalidSignature(t *testing.T) {
+	defer func() {
+		Equal(t, recover(), "invalid formatter func(int) error, must be func(T) string")
+	}()
+	RegisterFormatter(func(int) error { return nil })
+}
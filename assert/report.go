@@ -0,0 +1,120 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"gotest.tools/v3/internal/assert"
+)
+
+// Failure describes a failed assertion. A Failure is sent to every [Reporter]
+// registered with [RegisterReporter].
+type Failure struct {
+	// Assertion is the name of the assertion function, ex: assert.Equal.
+	Assertion string
+	// Expression is the source of the call to the assertion function.
+	Expression string
+	// Args is the source of each argument to the assertion function, not
+	// including the first argument, which is the testing.T.
+	Args []string
+	// File and Line are the location of the call to the assertion function.
+	File string
+	Line int
+	// Actual and Expected are the values that were compared. They are only
+	// set by assertions which compare two values, like Equal and DeepEqual.
+	Actual   interface{}
+	Expected interface{}
+	// Diff is the diff of the values. It is only set when the failure message
+	// includes a diff.
+	Diff string
+	// Message is the failure message, the same message that is printed
+	// using t.Log.
+	Message string
+}
+
+// Reporter is a function that receives each failed assertion. Reporters can be
+// used to collect failures in a structured format, in addition to the failure
+// message printed by the assertion.
+type Reporter func(failure Failure)
+
+// RegisterReporter adds a reporter which is called with the details of every
+// assertion that fails, from all tests in the package. The returned function
+// removes the reporter.
+//
+// Reporters may be called from multiple goroutines at the same time.
+func RegisterReporter(reporter Reporter) (unregister func()) {
+	return assert.RegisterReporter(func(failure assert.Failure) {
+		reporter(Failure(failure))
+	})
+}
+
+// JSONReporter returns a [Reporter] that writes each failure to w as a JSON
+// object on a single line.
+//
+// If the GOTESTTOOLS_REPORT_FILE environment variable is set, a JSONReporter
+// is registered which appends failures to the file named by the variable.
+func JSONReporter(w io.Writer) Reporter {
+	var mu sync.Mutex
+	return func(failure Failure) {
+		line, err := json.Marshal(jsonFailure{
+			Assertion:  failure.Assertion,
+			Expression: failure.Expression,
+			Args:       failure.Args,
+			File:       failure.File,
+			Line:       failure.Line,
+			Actual:     jsonValue(failure.Actual),
+			Expected:   jsonValue(failure.Expected),
+			Diff:       failure.Diff,
+			Message:    failure.Message,
+		})
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(append(line, '\n'))
+	}
+}
+
+type jsonFailure struct {
+	Assertion  string          `json:"assertion"`
+	Expression string          `json:"expression,omitempty"`
+	Args       []string        `json:"args,omitempty"`
+	File       string          `json:"file"`
+	Line       int             `json:"line"`
+	Actual     json.RawMessage `json:"actual,omitempty"`
+	Expected   json.RawMessage `json:"expected,omitempty"`
+	Diff       string          `json:"diff,omitempty"`
+	Message    string          `json:"message"`
+}
+
+// jsonValue encodes v as JSON. Values which can not be encoded as JSON, like
+// functions and channels, are encoded as a string using %v.
+func jsonValue(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		out, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	return out
+}
+
+const reportFileEnvVar = "GOTESTTOOLS_REPORT_FILE"
+
+func init() {
+	filename := os.Getenv(reportFileEnvVar)
+	if filename == "" {
+		return
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open %s=%s: %s\n", reportFileEnvVar, filename, err)
+		return
+	}
+	RegisterReporter(JSONReporter(file))
+}
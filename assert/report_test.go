@@ -0,0 +1,83 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestRegisterReporter(t *testing.T) {
+	var failures []Failure
+	unregister := RegisterReporter(func(failure Failure) {
+		failures = append(failures, failure)
+	})
+	defer unregister()
+
+	fakeT := &fakeTestingT{}
+	actual, expected := 3, 4
+	Check(fakeT, cmp.Equal(actual, expected))
+	Check(fakeT, actual == 3)
+	Equal(fakeT, "one\ntwo\n", "one\nthree\n")
+
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, got %d", len(failures))
+	}
+	DeepEqual(t, failures[0], Failure{
+		Assertion:  "assert.Check",
+		Expression: "Check(fakeT, cmp.Equal(actual, expected))",
+		Args:       []string{"cmp.Equal(actual, expected)"},
+		File:       failures[0].File,
+		Line:       failures[0].Line,
+		Actual:     3,
+		Expected:   4,
+		Message:    "assertion failed: 3 (actual int) != 4 (expected int)",
+	})
+	Equal(t, filepath.Base(failures[0].File), "report_test.go")
+	Equal(t, failures[1].Line, failures[0].Line+2)
+
+	Equal(t, failures[1].Assertion, "assert.Equal")
+	Equal(t, failures[1].Diff, "@@ -1,3 +1,3 @@\n one\n-two\n+three\n \n")
+	Assert(t, cmp.Contains(failures[1].Message, failures[1].Diff))
+
+	unregister()
+	Check(fakeT, false)
+	Equal(t, len(failures), 2)
+}
+
+func TestJSONReporter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	report := JSONReporter(buf)
+	report(Failure{
+		Assertion: "assert.Equal",
+		Args:      []string{"x", "y"},
+		File:      "example_test.go",
+		Line:      12,
+		Actual:    map[string]int{"a": 1},
+		Expected:  func() {},
+		Message:   "assertion failed",
+	})
+	report(Failure{Assertion: "assert.Assert", Message: "expression is false"})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	Equal(t, len(lines), 2)
+
+	var first map[string]interface{}
+	NilError(t, json.Unmarshal([]byte(lines[0]), &first))
+	DeepEqual(t, first, map[string]interface{}{
+		"assertion": "assert.Equal",
+		"args":      []interface{}{"x", "y"},
+		"file":      "example_test.go",
+		"line":      float64(12),
+		"actual":    map[string]interface{}{"a": float64(1)},
+		"expected":  first["expected"],
+		"message":   "assertion failed",
+	})
+	Assert(t, cmp.Regexp("^0x[0-9a-f]+$", first["expected"].(string)))
+
+	Equal(t, lines[1],
+		`{"assertion":"assert.Assert","file":"","line":0,"message":"expression is false"}`)
+}
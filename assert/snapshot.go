@@ -0,0 +1,177 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/internal/assert"
+	"gotest.tools/v3/internal/format"
+	"gotest.tools/v3/internal/source"
+)
+
+// SnapshotSettings are used to configure the behaviour of [Snapshot].
+type SnapshotSettings struct {
+	// Name of the snapshot file, relative to the ./testdata directory.
+	// Defaults to snapshots/<test name>.snap. When a test has more than one
+	// snapshot, the default name of each snapshot after the first includes
+	// a number, ex: snapshots/TestName-2.snap.
+	Name string
+	// MaxDepth is the number of nested values that are included in the
+	// snapshot. Values that are nested deeper are replaced with {...}.
+	// Defaults to 0, which means there is no limit.
+	MaxDepth int
+}
+
+// SnapshotOp is a function which accepts and modifies SnapshotSettings.
+type SnapshotOp func(settings *SnapshotSettings)
+
+// WithSnapshotName sets the name of the snapshot file.
+func WithSnapshotName(name string) SnapshotOp {
+	return func(settings *SnapshotSettings) {
+		settings.Name = name
+	}
+}
+
+// WithMaxDepth sets the number of nested values to include in the snapshot.
+func WithMaxDepth(depth int) SnapshotOp {
+	return func(settings *SnapshotSettings) {
+		settings.MaxDepth = depth
+	}
+}
+
+// Snapshot fails the test if the formatted value is not equal to the contents
+// of the snapshot file in ./testdata.
+//
+// The value is formatted in a format similar to Go syntax, one field or
+// element per line, so that the snapshot is easy to read and review. Map keys
+// are sorted, pointers are printed as & followed by the value they point to,
+// and types with a String or Error method are printed using that method.
+//
+// Running `go test pkgname -update` will write the formatted value to the
+// snapshot file.
+//
+// The default name of the snapshot file is created from the test name, which
+// requires t to have a Name method, like [testing.T].
+//
+// Snapshot uses [testing.T.FailNow] to fail the test. Like t.FailNow, Snapshot must be
+// called from the goroutine running the test function, not from other
+// goroutines created during the test.
+func Snapshot(t TestingT, value interface{}, ops ...SnapshotOp) {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	settings := &SnapshotSettings{}
+	for _, op := range ops {
+		op(settings)
+	}
+	if settings.Name == "" {
+		name, err := snapshotName(t)
+		if err != nil {
+			t.Log(err.Error())
+			t.FailNow()
+			return
+		}
+		settings.Name = name
+	}
+
+	actual := format.Value(value, format.ValueConfig{MaxDepth: settings.MaxDepth})
+	if !assert.Eval(t, assert.ArgsAfterT, compareSnapshot(actual, settings.Name)) {
+		t.FailNow()
+	}
+}
+
+type namedT interface {
+	Name() string
+}
+
+type cleanupT interface {
+	Cleanup(f func())
+}
+
+var snapshotCounts = struct {
+	sync.Mutex
+	counts map[string]int
+}{counts: make(map[string]int)}
+
+// snapshotName returns the default snapshot name for the next snapshot in
+// the test.
+func snapshotName(t TestingT) (string, error) {
+	named, ok := t.(namedT)
+	if !ok {
+		return "", fmt.Errorf("%T does not have a Name method, use WithSnapshotName", t)
+	}
+	testName := named.Name()
+
+	snapshotCounts.Lock()
+	defer snapshotCounts.Unlock()
+	count := snapshotCounts.counts[testName] + 1
+	if count == 1 {
+		if ct, ok := t.(cleanupT); ok {
+			ct.Cleanup(func() {
+				snapshotCounts.Lock()
+				defer snapshotCounts.Unlock()
+				delete(snapshotCounts.counts, testName)
+			})
+		}
+	}
+	snapshotCounts.counts[testName] = count
+
+	name := filepath.Join("snapshots", filepath.FromSlash(sanitizeTestName(testName)))
+	if count > 1 {
+		name += fmt.Sprintf("-%d", count)
+	}
+	return name + ".snap", nil
+}
+
+func sanitizeTestName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '*', '?', '"', '<', '>', '|', '\\':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func compareSnapshot(actual string, name string) cmp.Comparison {
+	return func() cmp.Result {
+		path := filepath.Join("testdata", name)
+		if source.IsUpdate() {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return cmp.ResultFromError(err)
+			}
+			return cmp.ResultFromError(os.WriteFile(path, []byte(actual), 0644))
+		}
+
+		expected, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return cmp.ResultFailure(fmt.Sprintf("snapshot %s does not exist", path) +
+				snapshotPostamble(path))
+		case err != nil:
+			return cmp.ResultFromError(err)
+		case string(expected) == actual:
+			return cmp.ResultSuccess
+		}
+
+		diff := format.UnifiedDiff(format.DiffConfig{
+			A:    string(expected),
+			B:    actual,
+			From: "expected",
+			To:   "actual",
+		})
+		return cmp.ResultFailure("\n" + diff + snapshotPostamble(path))
+	}
+}
+
+func snapshotPostamble(path string) string {
+	return fmt.Sprintf(`
+
+You can run 'go test . -update' to automatically update %s to the new expected value.
+`, path)
+}
@@ -0,0 +1,99 @@
+package assert
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/internal/source"
+)
+
+type snapshotStub struct {
+	Name     string
+	Labels   map[string]string
+	Parent   *snapshotStub
+	Children []*snapshotStub
+	Created  time.Time
+	Err      error
+	count    int
+}
+
+func TestSnapshot(t *testing.T) {
+	value := snapshotStub{
+		Name:   "root",
+		Labels: map[string]string{"z": "last", "a": "first"},
+		Parent: &snapshotStub{Name: "parent"},
+		Children: []*snapshotStub{
+			{Name: "one", count: 1},
+			{Name: "two", Labels: map[string]string{}},
+		},
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Err:     errors.New("the error"),
+		count:   2,
+	}
+	Snapshot(t, value)
+	Snapshot(t, []int{1, 2, 3})
+	Snapshot(t, value, WithMaxDepth(1), WithSnapshotName("snapshots/max-depth.snap"))
+}
+
+type namedFakeTestingT struct {
+	fakeTestingT
+	name string
+}
+
+func (f *namedFakeTestingT) Name() string {
+	return f.name
+}
+
+func TestSnapshot_Failures(t *testing.T) {
+	t.Run("does not match", func(t *testing.T) {
+		fakeT := &namedFakeTestingT{name: "TestSnapshot"}
+		Snapshot(fakeT, "not the value")
+
+		Assert(t, fakeT.failNowed)
+		Assert(t, strings.HasPrefix(fakeT.msgs[0], "assertion failed: \n--- expected\n+++ actual\n"),
+			fakeT.msgs[0])
+		Assert(t, strings.Contains(fakeT.msgs[0], `+"not the value"`), fakeT.msgs[0])
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		fakeT := &namedFakeTestingT{name: "TestSnapshot/missing"}
+		Snapshot(fakeT, 1)
+
+		path := filepath.Join("testdata", "snapshots", "TestSnapshot", "missing.snap")
+		expected := "assertion failed: snapshot " + path + " does not exist"
+		Assert(t, fakeT.failNowed)
+		Assert(t, strings.HasPrefix(fakeT.msgs[0], expected), fakeT.msgs[0])
+	})
+
+	t.Run("no name", func(t *testing.T) {
+		fakeT := &fakeTestingT{}
+		Snapshot(fakeT, 1)
+		expectFailNowed(t, fakeT,
+			"*assert.fakeTestingT does not have a Name method, use WithSnapshotName")
+	})
+}
+
+func TestSnapshot_Update(t *testing.T) {
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+		os.RemoveAll(filepath.Join("testdata", "snapshots", t.Name()))
+	})
+
+	fakeT := &namedFakeTestingT{name: t.Name() + "/first"}
+	Snapshot(fakeT, map[int]bool{2: true, 1: false})
+	Snapshot(fakeT, "second")
+	expectSuccess(t, &fakeT.fakeTestingT)
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "snapshots", t.Name(), "first.snap"))
+	NilError(t, err)
+	Equal(t, string(raw), "map[int]bool{\n\t1: false,\n\t2: true,\n}\n")
+
+	raw, err = os.ReadFile(filepath.Join("testdata", "snapshots", t.Name(), "first-2.snap"))
+	NilError(t, err)
+	Assert(t, strings.Contains(string(raw), `"second"`))
+}
@@ -0,0 +1,145 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+// problemResult is a CompareResult for a content matcher that fails with a
+// problem. The problem is reported as is, instead of being prefixed with
+// "content: " like the message of other results.
+type problemResult problem
+
+func (r problemResult) Success() bool {
+	return false
+}
+
+func (r problemResult) FailureMessage() string {
+	return string(r)
+}
+
+// matchContentWith returns a PathOp that compares the content of a file using
+// match. match returns an empty problem if the content matches.
+func matchContentWith(match func(content []byte) problem) PathOp {
+	return MatchFileContent(func(content []byte) CompareResult {
+		if p := match(content); p != "" {
+			return problemResult(p)
+		}
+		return cmp.ResultSuccess
+	})
+}
+
+// MatchJSONContent is a [PathOp] that updates a [Manifest] so that the file at
+// path must contain a JSON document equivalent to expected. The documents are
+// compared with [cmp.JSONEqual], values at paths which match any of the
+// filters are ignored, ex:
+//
+//	fs.MatchJSONContent(`{"name": "example"}`, opt.DocumentPath("$.id", "$.items[*].id"))
+func MatchJSONContent(expected string, ignore ...cmp.DocumentPathFilter) PathOp {
+	return func(path Path) error {
+		if err := json.Unmarshal([]byte(expected), new(json.RawMessage)); err != nil {
+			return fmt.Errorf("invalid expected JSON: %w", err)
+		}
+		return matchContentWith(func(content []byte) problem {
+			if err := json.Unmarshal(content, new(json.RawMessage)); err != nil {
+				return errProblem("content: failed to decode as JSON", err)
+			}
+			result := cmp.JSONEqual(content, expected, ignore...)()
+			if result.Success() {
+				return ""
+			}
+			msg := "documents are not equal"
+			if r, ok := result.(CompareResult); ok {
+				msg = r.FailureMessage()
+			}
+			return problem("content:\n" + indent(strings.TrimSuffix(msg, "\n"), "    "))
+		})(path)
+	}
+}
+
+// MatchRegexpContent is a [PathOp] that updates a [Manifest] so that the
+// content of the file at path must match the regular expression. The regular
+// expression is not anchored, use ^ and $ to match all of the content.
+func MatchRegexpContent(pattern string) PathOp {
+	return func(path Path) error {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		return matchContentWith(func(content []byte) problem {
+			if re.Match(content) {
+				return ""
+			}
+			return contentProblem(fmt.Sprintf("expected to match regexp %q", pattern), content)
+		})(path)
+	}
+}
+
+// MatchContentContains is a [PathOp] that updates a [Manifest] so that the
+// content of the file at path must contain substr.
+func MatchContentContains(substr string) PathOp {
+	return matchContentWith(func(content []byte) problem {
+		if bytes.Contains(content, []byte(substr)) {
+			return ""
+		}
+		return contentProblem(fmt.Sprintf("expected to contain %q", substr), content)
+	})
+}
+
+// contentProblem returns a problem with the reason, and the actual content
+// indented on the following lines.
+func contentProblem(reason string, content []byte) problem {
+	actual := strings.TrimSuffix(string(content), "\n")
+	return problem(fmt.Sprintf("content: %s, got:\n%s", reason, indent(actual, "    ")))
+}
+
+// MatchContentLines is a [PathOp] that updates a [Manifest] so that the file at
+// path must contain the lines. Lines may end with either \n or \r\n, and the
+// newline at the end of the last line is optional.
+func MatchContentLines(lines ...string) PathOp {
+	expected := []byte(strings.Join(lines, "\n") + "\n")
+	if len(lines) == 0 {
+		expected = nil
+	}
+	return matchContentWith(func(content []byte) problem {
+		actual := normalizeLines(content)
+		if bytes.Equal(expected, actual) {
+			return ""
+		}
+		return diffContent(expected, actual)
+	})
+}
+
+// normalizeLines replaces \r\n with \n, and adds a newline at the end of the
+// content if it is missing.
+func normalizeLines(content []byte) []byte {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	return content
+}
+
+// MatchGoldenContent is a [PathOp] that updates a [Manifest] so that the
+// content of the file at path must be equal to the content of the golden file.
+// The content is compared with [golden.String], see it for details about the
+// golden file, and how to update it.
+func MatchGoldenContent(filename string) PathOp {
+	return matchContentWith(func(content []byte) problem {
+		result := golden.String(string(content), filename)()
+		if result.Success() {
+			return ""
+		}
+		msg := "does not match the golden file"
+		if r, ok := result.(CompareResult); ok {
+			msg = r.FailureMessage()
+		}
+		return problem("content:\n" + indent(strings.Trim(msg, "\n"), "    "))
+	})
+}
@@ -0,0 +1,184 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/assert/opt"
+	"gotest.tools/v3/internal/source"
+)
+
+func TestMatchJSONContent(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("config.json", `{"name": "example", "id": 7, "items": [{"id": 1, "n": "a"}]}`))
+
+	t.Run("content matches", func(t *testing.T) {
+		manifest := Expected(t, WithFile("config.json", "",
+			MatchJSONContent(`{"items": [{"n": "a"}], "name": "example"}`,
+				opt.DocumentPath("$.id", "$.items[*].id"))))
+		assert.Assert(t, Equal(dir.Path(), manifest))
+	})
+
+	t.Run("content does not match", func(t *testing.T) {
+		manifest := Expected(t, WithFile("config.json", "",
+			MatchJSONContent(`{"name": "other", "id": 7, "items": []}`)))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, !result.Success())
+		assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/config.json
+  content:
+    documents are not equal:
+    $.items[0]: {"id":1,"n":"a"} != <missing>
+    $.name: "example" != "other"
+`, dir.Path()))
+	})
+
+	t.Run("large numbers", func(t *testing.T) {
+		assert.NilError(t, os.WriteFile(dir.Join("config.json"), []byte(`{"id": 9007199254740993}`), 0644))
+		manifest := Expected(t, WithFile("config.json", "",
+			MatchJSONContent(`{"id": 9007199254740992}`)))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, is.Contains(failureProblems(result),
+			"$.id: 9007199254740993 != 9007199254740992"))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		manifest := Expected(t, WithFile("config.json", "", MatchJSONContent(`{}`)))
+		assert.NilError(t, os.WriteFile(dir.Join("config.json"), []byte("{"), 0644))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, is.Contains(failureProblems(result),
+			"content: failed to decode as JSON: unexpected end of JSON input"))
+
+		err := MatchJSONContent(`{`)(&filePath{file: &file{}})
+		assert.ErrorContains(t, err, "invalid expected JSON")
+	})
+}
+
+func TestMatchRegexpContent(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("version", "v1.2.3\n"))
+
+	manifest := Expected(t, WithFile("version", "", MatchRegexpContent(`^v\d+\.\d+\.\d+\n$`)))
+	assert.Assert(t, Equal(dir.Path(), manifest))
+
+	manifest = Expected(t, WithFile("version", "", MatchRegexpContent(`^v2\.`)))
+	result := Equal(dir.Path(), manifest)()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/version
+  content: expected to match regexp "^v2\\.", got:
+    v1.2.3
+`, dir.Path()))
+
+	err := MatchRegexpContent(`(`)(&filePath{file: &file{}})
+	assert.ErrorContains(t, err, "missing closing )")
+}
+
+func TestMatchContentContains(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("log", "first\nsecond\n"))
+
+	manifest := Expected(t, WithFile("log", "", MatchContentContains("second")))
+	assert.Assert(t, Equal(dir.Path(), manifest))
+
+	manifest = Expected(t, WithFile("log", "", MatchContentContains("third")))
+	result := Equal(dir.Path(), manifest)()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/log
+  content: expected to contain "third", got:
+    first
+    second
+`, dir.Path()))
+}
+
+func TestMatchContentLines(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("unix", "one\ntwo\n"),
+		WithFile("windows", "one\r\ntwo"))
+
+	manifest := Expected(t,
+		WithFile("unix", "", MatchContentLines("one", "two")),
+		WithFile("windows", "", MatchContentLines("one", "two")))
+	assert.Assert(t, Equal(dir.Path(), manifest))
+
+	manifest = Expected(t,
+		WithFile("unix", "", MatchContentLines("one", "three")),
+		WithFile("windows", "", MatchContentLines("one", "two")))
+	result := Equal(dir.Path(), manifest)()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/unix
+  content:
+    --- expected
+    +++ actual
+    @@ -1,3 +1,3 @@
+     one
+    -three
+    +two
+     
+`, dir.Path()))
+}
+
+func TestMatchGoldenContent(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "content.golden")
+	assert.NilError(t, os.WriteFile(goldenFile, []byte("expected\n"), 0644))
+	dir := NewDir(t, t.Name(), WithFile("file", "actual\n"))
+	manifest := func() Manifest {
+		return Expected(t, WithFile("file", "", MatchGoldenContent(goldenFile)))
+	}
+
+	result := Equal(dir.Path(), manifest())()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/file
+  content:
+    --- expected
+    +++ actual
+    @@ -1,2 +1,2 @@
+    -expected
+    +actual
+     
+    
+    
+    You can run 'go test . -update' to automatically update %s to the new expected value.'
+`, dir.Path(), goldenFile))
+
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+	})
+	assert.Assert(t, Equal(dir.Path(), manifest()))
+	source.Update = false
+	assert.Assert(t, Equal(dir.Path(), manifest()))
+
+	content, err := os.ReadFile(goldenFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "actual\n")
+}
+
+func TestMatchGoldenContent_NormalizeCRLF(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "content.golden")
+	assert.NilError(t, os.WriteFile(goldenFile, []byte("one\ntwo\n"), 0644))
+	dir := NewDir(t, t.Name(), WithFile("file", "one\r\ntwo\r\n"))
+
+	manifest := Expected(t, WithFile("file", "", MatchGoldenContent(goldenFile)))
+	assert.Assert(t, Equal(dir.Path(), manifest))
+}
+
+func TestMatchGoldenContent_UpdateCreatesDirectory(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "missing", "content.golden")
+	dir := NewDir(t, t.Name(), WithFile("file", "actual\n"))
+
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+	})
+	manifest := Expected(t, WithFile("file", "", MatchGoldenContent(goldenFile)))
+	assert.Assert(t, Equal(dir.Path(), manifest))
+
+	content, err := os.ReadFile(goldenFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "actual\n")
+}
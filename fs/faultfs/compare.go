@@ -0,0 +1,80 @@
+package faultfs
+
+import (
+	"fmt"
+	"strings"
+
+	"gotest.tools/v3/assert/cmp"
+)
+
+// Attempted succeeds if fsys recorded at least one call of op on a path that
+// matches pattern. See [Fail] for the syntax of pattern.
+func Attempted(fsys *FaultFS, op Op, pattern string) cmp.Comparison {
+	return func() cmp.Result {
+		calls := fsys.Calls()
+		if len(matchingCalls(calls, op, pattern)) > 0 {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailure(fmt.Sprintf(
+			"expected %s of a path matching %s, got calls:\n%s",
+			op, pattern, formatCalls(calls)))
+	}
+}
+
+// NotAttempted succeeds if fsys did not record any call of op on a path that
+// matches pattern. See [Fail] for the syntax of pattern.
+func NotAttempted(fsys *FaultFS, op Op, pattern string) cmp.Comparison {
+	return func() cmp.Result {
+		matches := matchingCalls(fsys.Calls(), op, pattern)
+		if len(matches) == 0 {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailure(fmt.Sprintf(
+			"expected no %s of a path matching %s, got calls:\n%s",
+			op, pattern, formatCalls(matches)))
+	}
+}
+
+// AttemptedInOrder succeeds if fsys recorded the calls in expected, in the
+// same order. Other calls may be recorded before, after, or between the
+// expected calls. The Err field of the expected calls is not compared.
+func AttemptedInOrder(fsys *FaultFS, expected ...Call) cmp.Comparison {
+	return func() cmp.Result {
+		calls := fsys.Calls()
+		next := 0
+		for _, call := range calls {
+			if next < len(expected) &&
+				call.Op == expected[next].Op && call.Path == expected[next].Path {
+				next++
+			}
+		}
+		if next == len(expected) {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailure(fmt.Sprintf(
+			"expected %s after %d matching calls, got calls:\n%s",
+			expected[next], next, formatCalls(calls)))
+	}
+}
+
+func matchingCalls(calls []Call, op Op, pattern string) []Call {
+	rule := Fail(op, pattern, nil)
+	var matches []Call
+	for _, call := range calls {
+		if rule.matches(call.Op, call.Path) {
+			matches = append(matches, call)
+		}
+	}
+	return matches
+}
+
+func formatCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "  (none)\n"
+	}
+	buf := new(strings.Builder)
+	for _, call := range calls {
+		fmt.Fprintf(buf, "  %s\n", call)
+	}
+	return buf.String()
+}
@@ -0,0 +1,63 @@
+package faultfs
+
+import (
+	"syscall"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+type cmpFailure interface {
+	FailureMessage() string
+}
+
+func TestAttempted(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir, Fail(OpRename, "*", syscall.EXDEV))
+	assert.NilError(t, writeFile(fsys, "data.tmp", "content"))
+	assert.ErrorIs(t, fsys.Rename("data.tmp", "data.db"), syscall.EXDEV)
+
+	assert.Assert(t, Attempted(fsys, OpWrite, "*.tmp"))
+	assert.Assert(t, NotAttempted(fsys, OpWrite, "*.db"))
+
+	result := Attempted(fsys, OpRemove, "*.tmp")()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(),
+		`expected remove of a path matching *.tmp, got calls:
+  open data.tmp
+  write data.tmp
+  close data.tmp
+  rename data.tmp: rename data.tmp data.db: invalid cross-device link
+`)
+
+	result = NotAttempted(fsys, OpRename, "data.*")()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(),
+		`expected no rename of a path matching data.*, got calls:
+  rename data.tmp: rename data.tmp data.db: invalid cross-device link
+`)
+}
+
+func TestAttemptedInOrder(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir)
+	assert.NilError(t, writeFile(fsys, "data.tmp", "content"))
+	assert.NilError(t, fsys.Rename("data.tmp", "data.db"))
+
+	assert.Assert(t, AttemptedInOrder(fsys,
+		Call{Op: OpWrite, Path: "data.tmp"},
+		Call{Op: OpRename, Path: "data.tmp"}))
+
+	result := AttemptedInOrder(fsys,
+		Call{Op: OpRename, Path: "data.tmp"},
+		Call{Op: OpClose, Path: "data.tmp"})()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(),
+		`expected close data.tmp after 1 matching calls, got calls:
+  open data.tmp
+  write data.tmp
+  close data.tmp
+  rename data.tmp
+`)
+}
@@ -0,0 +1,470 @@
+/*
+Package faultfs provides a writable filesystem which injects errors into
+filesystem operations, and records the operations that were attempted. It is
+used to test how code handles errors like ENOSPC, EACCES, or EIO, and partial
+writes, without root privileges or a FUSE mount.
+
+Code under test uses the [FS] interface, or an interface with the subset of
+its methods that the code needs, instead of the os package. In tests the
+interface is implemented by a [FaultFS] created with [New]:
+
+	dir := fs.NewDir(t, "storage")
+	fsys := faultfs.New(dir,
+		faultfs.Fail(faultfs.OpWrite, "*.db", syscall.ENOSPC).OnCall(3))
+
+	err := store.Save(fsys, records)
+	assert.ErrorIs(t, err, syscall.ENOSPC)
+	assert.Assert(t, faultfs.NotAttempted(fsys, faultfs.OpRename, "*.db"))
+*/
+package faultfs // import "gotest.tools/v3/fs/faultfs"
+
+import (
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gotest.tools/v3/fs"
+)
+
+// FS is a writable filesystem. All names are slash separated paths relative to
+// the root of the filesystem, as defined by [io/fs.ValidPath].
+type FS interface {
+	iofs.FS
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Create(name string) (File, error)
+	ReadDir(name string) ([]os.DirEntry, error)
+	Stat(name string) (os.FileInfo, error)
+	Mkdir(name string, perm os.FileMode) error
+	MkdirAll(name string, perm os.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldname, newname string) error
+}
+
+// File is a file opened by an [FS].
+type File interface {
+	iofs.ReadDirFile
+	io.Writer
+	io.Seeker
+	Name() string
+	Sync() error
+}
+
+// Op is the name of a filesystem operation.
+type Op string
+
+// The operations which are recorded by [FaultFS], and which can fail with a
+// [Rule].
+const (
+	OpOpen      Op = "open"
+	OpRead      Op = "read"
+	OpWrite     Op = "write"
+	OpSeek      Op = "seek"
+	OpSync      Op = "sync"
+	OpClose     Op = "close"
+	OpReadDir   Op = "readdir"
+	OpStat      Op = "stat"
+	OpMkdir     Op = "mkdir"
+	OpRemove    Op = "remove"
+	OpRemoveAll Op = "removeall"
+	OpRename    Op = "rename"
+)
+
+// Call is a filesystem operation attempted by a [FaultFS].
+type Call struct {
+	Op Op
+	// Path is the slash separated path of the file, relative to the root of the
+	// filesystem. For OpRename it is the old name.
+	Path string
+	// Err is the error returned by the operation, which may be an error
+	// injected by a Rule.
+	Err error
+}
+
+func (c Call) String() string {
+	if c.Err != nil {
+		return fmt.Sprintf("%s %s: %s", c.Op, c.Path, c.Err)
+	}
+	return fmt.Sprintf("%s %s", c.Op, c.Path)
+}
+
+// Rule injects a fault into the operations which match the rule. Use [Fail]
+// or [PartialWrite] to create a Rule.
+type Rule struct {
+	op      Op
+	pattern string
+	err     error
+	call    int
+	// partial is the number of bytes written by a partial write, or -1.
+	partial int
+}
+
+// Fail returns a [Rule] which makes every op on a path that matches pattern
+// return err. err is wrapped in an [io/fs.PathError], or an [os.LinkError]
+// for OpRename, so it can be checked with [errors.Is].
+//
+// pattern uses the syntax of [path.Match]. A pattern without a slash is
+// matched against the base name of the path, otherwise it is matched against
+// the full slash separated path.
+func Fail(op Op, pattern string, err error) Rule {
+	return Rule{op: op, pattern: pattern, err: err, partial: -1}
+}
+
+// PartialWrite returns a [Rule] which makes every write to a path that matches
+// pattern write at most n bytes, and return [io.ErrShortWrite] if the write
+// was truncated. See [Fail] for the syntax of pattern.
+func PartialWrite(pattern string, n int) Rule {
+	return Rule{op: OpWrite, pattern: pattern, err: io.ErrShortWrite, partial: n}
+}
+
+// OnCall returns a copy of the rule that only applies to the nth call which
+// matches the rule, starting from 1.
+func (r Rule) OnCall(n int) Rule {
+	r.call = n
+	return r
+}
+
+func (r Rule) String() string {
+	s := fmt.Sprintf("%s %s", r.op, r.pattern)
+	if r.call > 0 {
+		s += fmt.Sprintf(" (call %d)", r.call)
+	}
+	return s
+}
+
+func (r Rule) matches(op Op, name string) bool {
+	if r.op != op {
+		return false
+	}
+	if !strings.Contains(r.pattern, "/") {
+		name = path.Base(name)
+	}
+	ok, _ := path.Match(r.pattern, name)
+	return ok
+}
+
+// FaultFS is an [FS] for a directory on the real filesystem, which fails
+// operations using a list of rules, and records every operation.
+type FaultFS struct {
+	root string
+
+	mu    sync.Mutex
+	rules []Rule
+	count []int
+	calls []Call
+}
+
+var _ FS = &FaultFS{}
+
+// New returns a [FaultFS] for the directory at dir. Usually dir is a
+// temporary directory created by [fs.NewDir]. When an operation matches more
+// than one rule, the first rule is used.
+func New(dir fs.Path, rules ...Rule) *FaultFS {
+	return &FaultFS{
+		root:  dir.Path(),
+		rules: rules,
+		count: make([]int, len(rules)),
+	}
+}
+
+// AddRule adds a rule to the filesystem. The rule applies to operations
+// attempted after it is added.
+func (f *FaultFS) AddRule(rule Rule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule)
+	f.count = append(f.count, 0)
+}
+
+// Calls returns the operations attempted on the filesystem, in order.
+func (f *FaultFS) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Reset removes the recorded operations, and resets the call count of every
+// rule.
+func (f *FaultFS) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.count = make([]int, len(f.rules))
+}
+
+// fault returns the rule that applies to the next call of op on name, or nil.
+func (f *FaultFS) fault(op Op, name string) *Rule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var match *Rule
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(op, name) {
+			continue
+		}
+		f.count[i]++
+		if match == nil && (rule.call == 0 || rule.call == f.count[i]) {
+			match = rule
+		}
+	}
+	return match
+}
+
+func (f *FaultFS) record(op Op, name string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Op: op, Path: name, Err: err})
+}
+
+// do runs fn, unless a rule matches the call, and records the call.
+func (f *FaultFS) do(op Op, name string, fn func(path string) error) error {
+	if !iofs.ValidPath(name) {
+		err := &iofs.PathError{Op: string(op), Path: name, Err: iofs.ErrInvalid}
+		f.record(op, name, err)
+		return err
+	}
+	var err error
+	if rule := f.fault(op, name); rule != nil {
+		err = &iofs.PathError{Op: string(op), Path: name, Err: rule.err}
+	} else {
+		err = relativeError(fn(f.path(name)), name)
+	}
+	f.record(op, name, err)
+	return err
+}
+
+// relativeError replaces the absolute path in an error from the os package
+// with name, the path relative to the root of the filesystem, so that errors
+// from real operations match the errors from faults. Other errors, like io.EOF,
+// are returned unchanged.
+func relativeError(err error, name string) error {
+	if pathErr, ok := err.(*iofs.PathError); ok {
+		return &iofs.PathError{Op: pathErr.Op, Path: name, Err: pathErr.Err}
+	}
+	return err
+}
+
+func (f *FaultFS) path(name string) string {
+	return filepath.Join(f.root, filepath.FromSlash(name))
+}
+
+// Open opens the file for reading.
+func (f *FaultFS) Open(name string) (iofs.File, error) {
+	file, err := f.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Create creates or truncates the file, like [os.Create].
+func (f *FaultFS) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// OpenFile opens the file with the flag and perm, like [os.OpenFile].
+func (f *FaultFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	var file *os.File
+	err := f.do(OpOpen, name, func(path string) error {
+		var err error
+		file, err = os.OpenFile(path, flag, perm)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &faultFile{fsys: f, name: name, file: file}, nil
+}
+
+// ReadDir reads the directory, like [os.ReadDir].
+func (f *FaultFS) ReadDir(name string) ([]os.DirEntry, error) {
+	var entries []os.DirEntry
+	err := f.do(OpReadDir, name, func(path string) error {
+		var err error
+		entries, err = os.ReadDir(path)
+		return err
+	})
+	return entries, err
+}
+
+// Stat returns the [os.FileInfo] of the file, like [os.Stat].
+func (f *FaultFS) Stat(name string) (os.FileInfo, error) {
+	var info os.FileInfo
+	err := f.do(OpStat, name, func(path string) error {
+		var err error
+		info, err = os.Stat(path)
+		return err
+	})
+	return info, err
+}
+
+// Mkdir creates a directory, like [os.Mkdir].
+func (f *FaultFS) Mkdir(name string, perm os.FileMode) error {
+	return f.do(OpMkdir, name, func(path string) error {
+		return os.Mkdir(path, perm)
+	})
+}
+
+// MkdirAll creates a directory and any missing parents, like [os.MkdirAll].
+// Each directory that is created is recorded as a separate OpMkdir.
+func (f *FaultFS) MkdirAll(name string, perm os.FileMode) error {
+	if !iofs.ValidPath(name) {
+		return f.do(OpMkdir, name, nil)
+	}
+	if name == "." {
+		return nil
+	}
+	if info, err := os.Stat(f.path(name)); err == nil && info.IsDir() {
+		return nil
+	}
+	if err := f.MkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+	err := f.Mkdir(name, perm)
+	if err != nil && os.IsExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Remove removes a file or empty directory, like [os.Remove].
+func (f *FaultFS) Remove(name string) error {
+	return f.do(OpRemove, name, os.Remove)
+}
+
+// RemoveAll removes the path and anything it contains, like [os.RemoveAll].
+func (f *FaultFS) RemoveAll(name string) error {
+	return f.do(OpRemoveAll, name, os.RemoveAll)
+}
+
+// Rename renames oldname to newname, like [os.Rename]. Rules for OpRename
+// are matched against oldname.
+func (f *FaultFS) Rename(oldname, newname string) error {
+	linkError := func(err error) error {
+		return &os.LinkError{Op: string(OpRename), Old: oldname, New: newname, Err: err}
+	}
+	var err error
+	switch {
+	case !iofs.ValidPath(oldname) || !iofs.ValidPath(newname):
+		err = linkError(iofs.ErrInvalid)
+	default:
+		if rule := f.fault(OpRename, oldname); rule != nil {
+			err = linkError(rule.err)
+		} else {
+			err = os.Rename(f.path(oldname), f.path(newname))
+			if linkErr, ok := err.(*os.LinkError); ok {
+				err = linkError(linkErr.Err)
+			}
+		}
+	}
+	f.record(OpRename, oldname, err)
+	return err
+}
+
+type faultFile struct {
+	fsys *FaultFS
+	name string
+	file *os.File
+}
+
+func (f *faultFile) Name() string {
+	return f.name
+}
+
+func (f *faultFile) Read(p []byte) (int, error) {
+	var n int
+	err := f.fsys.do(OpRead, f.name, func(string) error {
+		var err error
+		n, err = f.file.Read(p)
+		return err
+	})
+	return n, err
+}
+
+func (f *faultFile) Write(p []byte) (int, error) {
+	if rule := f.fsys.fault(OpWrite, f.name); rule != nil {
+		return f.partialWrite(p, rule)
+	}
+	n, err := f.file.Write(p)
+	err = relativeError(err, f.name)
+	f.fsys.record(OpWrite, f.name, err)
+	return n, err
+}
+
+// partialWrite writes the part of p allowed by the rule, and returns the
+// error from the rule.
+func (f *faultFile) partialWrite(p []byte, rule *Rule) (int, error) {
+	var n int
+	if rule.partial >= 0 {
+		if rule.partial >= len(p) {
+			n, err := f.file.Write(p)
+			err = relativeError(err, f.name)
+			f.fsys.record(OpWrite, f.name, err)
+			return n, err
+		}
+		var err error
+		if n, err = f.file.Write(p[:rule.partial]); err != nil {
+			err = relativeError(err, f.name)
+			f.fsys.record(OpWrite, f.name, err)
+			return n, err
+		}
+	}
+	err := &iofs.PathError{Op: string(OpWrite), Path: f.name, Err: rule.err}
+	f.fsys.record(OpWrite, f.name, err)
+	return n, err
+}
+
+func (f *faultFile) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	err := f.fsys.do(OpSeek, f.name, func(string) error {
+		var err error
+		pos, err = f.file.Seek(offset, whence)
+		return err
+	})
+	return pos, err
+}
+
+func (f *faultFile) Sync() error {
+	return f.fsys.do(OpSync, f.name, func(string) error {
+		return f.file.Sync()
+	})
+}
+
+func (f *faultFile) Stat() (os.FileInfo, error) {
+	var info os.FileInfo
+	err := f.fsys.do(OpStat, f.name, func(string) error {
+		var err error
+		info, err = f.file.Stat()
+		return err
+	})
+	return info, err
+}
+
+func (f *faultFile) ReadDir(n int) ([]os.DirEntry, error) {
+	var entries []os.DirEntry
+	err := f.fsys.do(OpReadDir, f.name, func(string) error {
+		var err error
+		entries, err = f.file.ReadDir(n)
+		return err
+	})
+	return entries, err
+}
+
+// Close closes the file. The file is closed even when a rule makes Close
+// return an error, so that tests do not leak file descriptors.
+func (f *faultFile) Close() error {
+	if rule := f.fsys.fault(OpClose, f.name); rule != nil {
+		_ = f.file.Close()
+		err := &iofs.PathError{Op: string(OpClose), Path: f.name, Err: rule.err}
+		f.fsys.record(OpClose, f.name, err)
+		return err
+	}
+	err := relativeError(f.file.Close(), f.name)
+	f.fsys.record(OpClose, f.name, err)
+	return err
+}
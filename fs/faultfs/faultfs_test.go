@@ -0,0 +1,154 @@
+package faultfs
+
+import (
+	"errors"
+	"io"
+	iofs "io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"testing/fstest"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func writeFile(fsys FS, name string, chunks ...string) error {
+	f, err := fsys.Create(name)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, err := io.WriteString(f, chunk); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func TestFail_OnCall(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir, Fail(OpWrite, "*.db", syscall.ENOSPC).OnCall(3))
+
+	err := writeFile(fsys, "data.db", "one", "two", "three", "four")
+	assert.Assert(t, errors.Is(err, syscall.ENOSPC), "got %v", err)
+	assert.Error(t, err, "write data.db: no space left on device")
+
+	assert.NilError(t, writeFile(fsys, "other.txt", "one", "two", "three"))
+	assert.NilError(t, writeFile(fsys, "data.db", "one"))
+
+	content, err := os.ReadFile(dir.Join("other.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "onetwothree")
+}
+
+func TestFail_Patterns(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("sub", fs.WithFile("a.txt", "a")),
+		fs.WithFile("a.txt", "a"))
+	fsys := New(dir,
+		Fail(OpOpen, "sub/*.txt", syscall.EACCES),
+		Fail(OpMkdir, "new", syscall.EIO),
+		Fail(OpRename, "a.txt", syscall.EXDEV))
+
+	f, err := fsys.Open("a.txt")
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+	_, err = fsys.Open("sub/a.txt")
+	assert.Assert(t, errors.Is(err, syscall.EACCES), "got %v", err)
+
+	err = fsys.MkdirAll("new/nested", 0755)
+	assert.Assert(t, errors.Is(err, syscall.EIO), "got %v", err)
+
+	err = fsys.Rename("a.txt", "b.txt")
+	var linkErr *os.LinkError
+	assert.Assert(t, errors.As(err, &linkErr), "got %T", err)
+	assert.Assert(t, errors.Is(err, syscall.EXDEV))
+	assert.Equal(t, linkErr.New, "b.txt")
+
+	_, err = fsys.Stat("../a.txt")
+	assert.Assert(t, errors.Is(err, os.ErrInvalid), "got %v", err)
+}
+
+func TestRelativeErrors(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("a.txt", "a"))
+	fsys := New(dir)
+
+	_, err := fsys.Open("missing.txt")
+	assert.Assert(t, errors.Is(err, os.ErrNotExist), "got %v", err)
+	var pathErr *iofs.PathError
+	assert.Assert(t, errors.As(err, &pathErr), "got %T", err)
+	assert.Equal(t, pathErr.Path, "missing.txt")
+
+	err = fsys.Rename("missing.txt", "b.txt")
+	var linkErr *os.LinkError
+	assert.Assert(t, errors.As(err, &linkErr), "got %T", err)
+	assert.Equal(t, linkErr.Old, "missing.txt")
+	assert.Equal(t, linkErr.New, "b.txt")
+
+	f, err := fsys.Open("a.txt")
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+	err = f.Close()
+	assert.Assert(t, errors.Is(err, os.ErrClosed), "got %v", err)
+	assert.Error(t, err, "close a.txt: file already closed")
+}
+
+func TestPartialWrite(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir, PartialWrite("*.log", 3).OnCall(2))
+
+	err := writeFile(fsys, "app.log", "first\n", "second\n", "third\n")
+	assert.Assert(t, errors.Is(err, io.ErrShortWrite), "got %v", err)
+
+	content, err := os.ReadFile(dir.Join("app.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "first\nsec")
+}
+
+func TestFaultFS_Close(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir, Fail(OpClose, "*", syscall.EIO))
+
+	err := writeFile(fsys, "file", "content")
+	assert.Assert(t, errors.Is(err, syscall.EIO), "got %v", err)
+	assert.Assert(t, Attempted(fsys, OpClose, "file"))
+
+	content, err := os.ReadFile(dir.Join("file"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content")
+}
+
+func TestFaultFS_Calls(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	fsys := New(dir, Fail(OpSync, "*.db", syscall.EIO))
+
+	f, err := fsys.Create("x.db")
+	assert.NilError(t, err)
+	_, err = f.Write([]byte("data"))
+	assert.NilError(t, err)
+	syncErr := f.Sync()
+	assert.Assert(t, errors.Is(syncErr, syscall.EIO))
+	assert.NilError(t, f.Close())
+	assert.NilError(t, fsys.Remove("x.db"))
+
+	expected := []Call{
+		{Op: OpOpen, Path: "x.db"},
+		{Op: OpWrite, Path: "x.db"},
+		{Op: OpSync, Path: "x.db", Err: syncErr},
+		{Op: OpClose, Path: "x.db"},
+		{Op: OpRemove, Path: "x.db"},
+	}
+	assert.DeepEqual(t, fsys.Calls(), expected)
+
+	fsys.Reset()
+	assert.Equal(t, len(fsys.Calls()), 0)
+}
+
+func TestFaultFS_ImplementsFS(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a.txt", "a"),
+		fs.WithDir("sub", fs.WithFile("b.txt", "b")))
+	assert.NilError(t, fstest.TestFS(New(dir), "a.txt", "sub/b.txt"))
+}
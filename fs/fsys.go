@@ -0,0 +1,109 @@
+package fs
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gotest.tools/v3/assert"
+)
+
+// ManifestFromFS creates a [Manifest] from the directory root in fsys. root is
+// a slash separated path, use "." for the root of fsys. ManifestFromFS can be
+// used with [Equal] to compare a directory to the files in an [embed.FS],
+// [testing/fstest.MapFS], or any other [io/fs.FS].
+//
+// Most implementations of [io/fs.FS] do not have a meaningful file mode or
+// owner, so the manifest matches any mode, uid, and gid. Symlinks are not
+// supported.
+func ManifestFromFS(t assert.TestingT, fsys iofs.FS, root string) Manifest {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	manifest, err := manifestFromFS(fsys, root)
+	assert.NilError(t, err)
+	return manifest
+}
+
+func manifestFromFS(fsys iofs.FS, root string) (Manifest, error) {
+	info, err := iofs.Stat(fsys, root)
+	switch {
+	case err != nil:
+		return Manifest{}, err
+	case !info.IsDir():
+		return Manifest{}, fmt.Errorf("path %s must be a directory", root)
+	}
+
+	dir, err := newDirectoryFromFS(fsys, root)
+	if err != nil {
+		return Manifest{}, err
+	}
+	ignoreResourceProperties(dir)
+	return Manifest{root: dir}, nil
+}
+
+func newDirectoryFromFS(fsys iofs.FS, dirPath string) (*directory, error) {
+	dir := newDirectoryWithDefaults()
+	entries, err := iofs.ReadDir(fsys, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		fullPath := path.Join(dirPath, entry.Name())
+		switch {
+		case entry.IsDir():
+			dir.items[entry.Name()], err = newDirectoryFromFS(fsys, fullPath)
+		case entry.Type()&iofs.ModeSymlink != 0:
+			err = fmt.Errorf("symlink %s is not supported", fullPath)
+		default:
+			dir.items[entry.Name()], err = newFileFromFS(fsys, fullPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+func newFileFromFS(fsys iofs.FS, filePath string) (*file, error) {
+	content, err := iofs.ReadFile(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &file{
+		resource: newResource(defaultFileMode),
+		content:  newBytesContent(content),
+	}, nil
+}
+
+// FromFS copies the files and directories from fsys into the directory at
+// [Path]. Files are created with mode 0644 and directories with mode 0755.
+// Use [io/fs.Sub] to copy a subdirectory of fsys. Symlinks are not supported.
+func FromFS(fsys iofs.FS) PathOp {
+	return func(p Path) error {
+		if _, ok := p.(manifestDirectory); ok {
+			return fmt.Errorf("use ManifestFromFS")
+		}
+		return iofs.WalkDir(fsys, ".", func(name string, entry iofs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			dest := filepath.Join(p.Path(), filepath.FromSlash(name))
+			switch {
+			case name == ".":
+				return nil
+			case entry.IsDir():
+				return os.MkdirAll(dest, 0755)
+			case entry.Type()&iofs.ModeSymlink != 0:
+				return fmt.Errorf("symlink %s is not supported", name)
+			}
+			content, err := iofs.ReadFile(fsys, name)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(dest, content, defaultFileMode)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package fs
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var mapFS = fstest.MapFS{
+	"config.yaml":       {Data: []byte("name: example\n")},
+	"data/one.txt":      {Data: []byte("one\n"), Mode: 0400},
+	"data/nested/2.txt": {Data: []byte("two\n")},
+}
+
+func TestManifestFromFS(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("config.yaml", "name: example\n"),
+		WithDir("data",
+			WithFile("one.txt", "one\n"),
+			WithDir("nested", WithFile("2.txt", "two\n"))))
+
+	assert.Assert(t, Equal(dir.Path(), ManifestFromFS(t, mapFS, ".")))
+
+	t.Run("subdirectory", func(t *testing.T) {
+		result := Equal(dir.Path(), ManifestFromFS(t, mapFS, "data"))()
+		assert.Assert(t, !result.Success())
+		assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/
+  nested: expected directory to exist
+  one.txt: expected file to exist
+  config.yaml: unexpected file
+  data: unexpected directory
+`, dir.Path()))
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := manifestFromFS(mapFS, "config.yaml")
+		assert.Error(t, err, "path config.yaml must be a directory")
+	})
+}
+
+func TestFromFS(t *testing.T) {
+	dir := NewDir(t, t.Name(), FromFS(mapFS))
+
+	expected := Expected(t,
+		WithMode(0700),
+		WithFile("config.yaml", "name: example\n"),
+		WithDir("data",
+			WithFile("one.txt", "one\n"),
+			WithDir("nested", WithFile("2.txt", "two\n"))))
+	assert.Assert(t, Equal(dir.Path(), expected))
+
+	t.Run("with a manifest", func(t *testing.T) {
+		err := FromFS(mapFS)(&directoryPath{directory: newDirectoryWithDefaults()})
+		assert.Assert(t, is.ErrorContains(err, "use ManifestFromFS"))
+	})
+}
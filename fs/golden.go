@@ -0,0 +1,225 @@
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/internal/source"
+)
+
+// AssertGoldenDir compares the directory at actualDir to the golden directory
+// at goldenDir, and fails the test if the files, directories, or symlinks in
+// the two directories are different. goldenDir is usually a directory in
+// testdata that is checked into version control.
+//
+// The file mode, uid, gid, extended attributes, and hard links of the files are
+// not compared, because they are not preserved by most version control
+// systems. Symlinks with an absolute target in the directory are compared
+// as if the target was relative to the symlink. Empty directories are also
+// compared, but they are not stored by git, so a golden directory should not
+// contain empty directories.
+//
+// Running `go test pkgname -update` will update goldenDir to match actualDir.
+// Files and symlinks that are missing or different are written to goldenDir,
+// and files, symlinks, and directories that are not in actualDir are removed
+// from goldenDir. Symlinks are written with a relative target, the update fails
+// if actualDir contains a symlink with an absolute target outside of
+// actualDir.
+func AssertGoldenDir(t assert.TestingT, actualDir, goldenDir string, msgAndArgs ...interface{}) {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	assert.Assert(t, goldenDirEqual(actualDir, goldenDir), msgAndArgs...)
+}
+
+func goldenDirEqual(actualDir, goldenDir string) cmp.Comparison {
+	return func() cmp.Result {
+		if source.IsUpdate() {
+			if err := syncDir(actualDir, actualDir, goldenDir); err != nil {
+				return cmp.ResultFailure(fmt.Sprintf("failed to update golden directory: %s", err))
+			}
+		}
+
+		expected, err := manifestFromDir(goldenDir, false)
+		if err != nil {
+			return cmp.ResultFromError(err)
+		}
+		ignoreResourceProperties(expected.root)
+		relativeSymlinks(goldenDir, goldenDir, expected.root)
+		actual, err := manifestFromDir(actualDir, false)
+		if err != nil {
+			return cmp.ResultFromError(err)
+		}
+		relativeSymlinks(actualDir, actualDir, actual.root)
+
+		failures := eqDirectory(string(os.PathSeparator), expected.root, actual.root)
+		if len(failures) == 0 {
+			return cmp.ResultSuccess
+		}
+		msg := fmt.Sprintf("directory %s does not match golden directory %s:\n",
+			actualDir, goldenDir)
+		postamble := fmt.Sprintf(
+			"\nYou can run 'go test . -update' to automatically update %s to the new expected value.\n",
+			goldenDir)
+		return cmp.ResultFailure(msg + formatFailures(failures) + postamble)
+	}
+}
+
+// ignoreResourceProperties updates the resources in dir, and all the entries
+// in dir, to match any mode, uid, gid, and extended attributes. Hard links are
+// compared as regular files.
+func ignoreResourceProperties(dir *directory) {
+	ignore := func(r *resource) {
+		r.mode = anyFileMode
+		r.uid = currentUID()
+		r.gid = currentGID()
+		r.xattrs = nil
+	}
+	ignore(&dir.resource)
+	for _, entry := range dir.items {
+		switch typed := entry.(type) {
+		case *directory:
+			ignoreResourceProperties(typed)
+		case *file:
+			ignore(&typed.resource)
+			typed.hardlink = nil
+		case *symlink:
+			ignore(&typed.resource)
+		case *specialFile:
+			ignore(&typed.resource)
+		}
+	}
+}
+
+// relativeSymlinks updates the symlinks in dir, and all the directories in
+// dir, which have an absolute target in root, so that the target is relative
+// to the directory of the symlink. dirPath is the path of dir.
+func relativeSymlinks(root, dirPath string, dir *directory) {
+	for name, entry := range dir.items {
+		switch typed := entry.(type) {
+		case *directory:
+			relativeSymlinks(root, filepath.Join(dirPath, name), typed)
+		case *symlink:
+			if target, err := relativeLinkTarget(root, dirPath, typed.target); err == nil {
+				typed.target = target
+			}
+		}
+	}
+}
+
+// relativeLinkTarget returns target relative to dirPath. An error is returned
+// if target is an absolute path outside of root. Relative targets are returned
+// unchanged.
+func relativeLinkTarget(root, dirPath, target string) (string, error) {
+	if !filepath.IsAbs(target) {
+		return target, nil
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("target %s is outside of %s", target, root)
+	}
+	return filepath.Rel(dirPath, target)
+}
+
+// syncDir updates the directory at to so that it contains the same files,
+// directories, and symlinks as the directory at from. Files are only written
+// when the content is different. root is the directory that contains from,
+// and is used to make the target of symlinks relative.
+func syncDir(root, from, to string) error {
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return err
+	}
+	fromEntries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	toEntries, err := os.ReadDir(to)
+	if err != nil {
+		return err
+	}
+
+	fromTypes := make(map[string]os.FileMode, len(fromEntries))
+	for _, entry := range fromEntries {
+		fromTypes[entry.Name()] = entry.Type()
+	}
+	for _, entry := range toEntries {
+		if typ, ok := fromTypes[entry.Name()]; ok && typ == entry.Type() {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(to, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range fromEntries {
+		fromPath := filepath.Join(from, entry.Name())
+		toPath := filepath.Join(to, entry.Name())
+		switch {
+		case entry.IsDir():
+			err = syncDir(root, fromPath, toPath)
+		case entry.Type()&os.ModeSymlink != 0:
+			err = syncSymlink(root, fromPath, toPath)
+		case entry.Type().IsRegular():
+			err = syncFile(fromPath, toPath)
+		default:
+			// reading a named pipe would block, and special files can not be
+			// stored in version control.
+			err = fmt.Errorf("%s: %s can not be written to a golden directory",
+				fromPath, specialFileType(entry.Type()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// specialFileType returns the name of the type of a file which is not a
+// regular file, directory, or symlink.
+func specialFileType(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	}
+	return "special file"
+}
+
+func syncSymlink(root, from, to string) error {
+	linkTarget, err := os.Readlink(from)
+	if err != nil {
+		return err
+	}
+	linkTarget, err = relativeLinkTarget(root, filepath.Dir(from), linkTarget)
+	if err != nil {
+		return fmt.Errorf("symlink %s: %w", from, err)
+	}
+	if existing, err := os.Readlink(to); err == nil {
+		if existing == linkTarget {
+			return nil
+		}
+		if err := os.Remove(to); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(linkTarget, to)
+}
+
+func syncFile(from, to string) error {
+	content, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if existing, err := os.ReadFile(to); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	return os.WriteFile(to, content, defaultFileMode)
+}
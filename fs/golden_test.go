@@ -0,0 +1,146 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/internal/source"
+	"gotest.tools/v3/skip"
+)
+
+func TestAssertGoldenDir(t *testing.T) {
+	actual := NewDir(t, t.Name(), FromDir("testdata/copy-test"))
+	AssertGoldenDir(t, actual.Path(), "testdata/copy-test")
+}
+
+func TestGoldenDirEqual_Failure(t *testing.T) {
+	golden := NewDir(t, "golden",
+		WithFile("same", "content\n"),
+		WithFile("changed", "one\ntwo\n"),
+		WithFile("removed", ""),
+		WithDir("sub", WithFile("file", "")))
+	actual := NewDir(t, "actual",
+		WithFile("same", "content\n", WithMode(0600)),
+		WithFile("changed", "one\nthree\n"),
+		WithFile("added", ""),
+		WithDir("sub"))
+
+	result := goldenDirEqual(actual.Path(), golden.Path())()
+	assert.Assert(t, !result.Success())
+	expected := fmtExpected(`directory %s does not match golden directory %s:
+/
+  removed: expected file to exist
+  added: unexpected file
+/changed
+  content:
+    --- expected
+    +++ actual
+    @@ -1,3 +1,3 @@
+     one
+    -two
+    +three
+     
+/sub
+  file: expected file to exist
+
+You can run 'go test . -update' to automatically update %s to the new expected value.
+`, actual.Path(), golden.Path(), golden.Path())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+}
+
+func TestGoldenDirEqual_Update(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "symlinks require admin on windows")
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+	})
+
+	golden := NewDir(t, "golden",
+		WithFile("changed", "old"),
+		WithFile("removed", ""),
+		WithSymlink("link", "removed"),
+		WithDir("file-to-dir"),
+		WithDir("sub", WithFile("removed", "")))
+	actual := NewDir(t, "actual",
+		WithFile("changed", "new"),
+		WithFile("added", "added"),
+		WithSymlink("link", "changed"),
+		WithFile("file-to-dir", ""),
+		WithDir("sub",
+			WithDir("nested", WithFile("file", "nested")),
+			WithSymlink("up", "../changed")))
+
+	assert.Assert(t, goldenDirEqual(actual.Path(), golden.Path()))
+
+	source.Update = false
+	assert.Assert(t, goldenDirEqual(actual.Path(), golden.Path()))
+	target, err := os.Readlink(golden.Join("link"))
+	assert.NilError(t, err)
+	assert.Equal(t, target, "changed")
+	target, err = os.Readlink(golden.Join("sub", "up"))
+	assert.NilError(t, err)
+	assert.Equal(t, target, filepath.Join("..", "changed"))
+}
+
+func TestGoldenDirEqual_UpdateSymlinkOutsideDir(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "symlinks require admin on windows")
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+	})
+
+	outside := NewFile(t, "outside")
+	golden := NewDir(t, "golden")
+	actual := NewDir(t, "actual")
+	assert.NilError(t, os.Symlink(outside.Path(), actual.Join("link")))
+
+	result := goldenDirEqual(actual.Path(), golden.Path())()
+	assert.Assert(t, !result.Success())
+	assert.Assert(t, cmp.Contains(result.(cmpFailure).FailureMessage(),
+		fmt.Sprintf("failed to update golden directory: symlink %s: target %s is outside of %s",
+			actual.Join("link"), outside.Path(), actual.Path())))
+}
+
+func TestGoldenDirEqual_UpdateFIFO(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "named pipes are not supported on windows")
+	source.Update = true
+	t.Cleanup(func() {
+		source.Update = false
+	})
+
+	golden := NewDir(t, "golden")
+	actual := NewDir(t, "actual", WithFIFO("pipe"))
+
+	result := goldenDirEqual(actual.Path(), golden.Path())()
+	assert.Assert(t, !result.Success())
+	assert.Assert(t, cmp.Contains(result.(cmpFailure).FailureMessage(),
+		fmt.Sprintf("failed to update golden directory: %s: fifo can not be written to a golden directory",
+			actual.Join("pipe"))))
+}
+
+func TestGoldenDirEqual_IgnoresHardlinksAndXattrs(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "hard links are not supported on windows")
+	golden := NewDir(t, "golden",
+		WithFile("file", "content"),
+		WithHardlink("link", "file"))
+	actual := NewDir(t, "actual",
+		WithFile("file", "content"),
+		WithFile("link", "other"))
+
+	result := goldenDirEqual(actual.Path(), golden.Path())()
+	assert.Assert(t, !result.Success())
+	assert.Assert(t, cmp.Contains(result.(cmpFailure).FailureMessage(), "/link\n  content:"))
+
+	assert.NilError(t, os.WriteFile(actual.Join("link"), []byte("content"), 0644))
+	expected, err := manifestFromDir(golden.Path(), false)
+	assert.NilError(t, err)
+	expected.root.xattrs = map[string]string{"user.golden": "value"}
+	ignoreResourceProperties(expected.root)
+	assert.Assert(t, expected.root.xattrs == nil)
+	assert.Assert(t, goldenDirEqual(actual.Path(), golden.Path()))
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -19,13 +20,31 @@ type resource struct {
 	mode os.FileMode
 	uid  uint32
 	gid  uint32
+	// xattrs are the extended attributes of the resource. Only the attributes
+	// in the expected resource are compared.
+	xattrs map[string]string
+	mtime  time.Time
+	// matchMtime are used to compare the mtime of the actual resource, the
+	// mtime must match all of them. The mtime is not compared when matchMtime
+	// is empty.
+	matchMtime []mtimeMatcher
 }
 
+// mtimeMatcher returns true if mtime matches the expectation, and a
+// description of the expected value.
+type mtimeMatcher func(mtime time.Time) (bool, string)
+
 type file struct {
 	resource
 	content             io.ReadCloser
 	ignoreCariageReturn bool
 	compareContentFunc  func(b []byte) CompareResult
+	// hardlink is the file that this file is a hard link to, or nil if the
+	// file is not expected to be a hard link to another file in the manifest.
+	hardlink *file
+	// digest is used to compare the content when the manifest was loaded by
+	// Manifest.UnmarshalJSON, which only stores a hash of the content.
+	digest *contentDigest
 }
 
 func (f *file) Type() string {
@@ -41,6 +60,16 @@ func (f *symlink) Type() string {
 	return "symlink"
 }
 
+// specialFile is a named pipe, socket, or device.
+type specialFile struct {
+	resource
+	typ string
+}
+
+func (f *specialFile) Type() string {
+	return f.typ
+}
+
 type directory struct {
 	resource
 	items         map[string]dirEntry
@@ -63,12 +92,15 @@ func ManifestFromDir(t assert.TestingT, path string) Manifest {
 		ht.Helper()
 	}
 
-	manifest, err := manifestFromDir(path)
+	manifest, err := manifestFromDir(path, true)
 	assert.NilError(t, err)
 	return manifest
 }
 
-func manifestFromDir(path string) (Manifest, error) {
+// manifestFromDir reads the directory at path. The extended attributes of the
+// entries are only read when readXattrs is true, because reading them requires
+// at least one system call for every entry.
+func manifestFromDir(path string, readXattrs bool) (Manifest, error) {
 	info, err := os.Stat(path)
 	switch {
 	case err != nil:
@@ -77,11 +109,42 @@ func manifestFromDir(path string) (Manifest, error) {
 		return Manifest{}, fmt.Errorf("path %s must be a directory", path)
 	}
 
-	directory, err := newDirectory(path, info)
+	r := &dirReader{readXattrs: readXattrs}
+	directory, err := r.newDirectory(path, info)
 	return Manifest{root: directory}, err
 }
 
-func newDirectory(path string, info os.FileInfo) (*directory, error) {
+// dirReader reads the entries of a directory into a manifest.
+type dirReader struct {
+	links      hardlinks
+	readXattrs bool
+}
+
+// hardlinks are the files in a manifest that have more than one link, used to
+// find the files that are hard links to the same file.
+type hardlinks []linkedFile
+
+type linkedFile struct {
+	info os.FileInfo
+	file *file
+}
+
+// add f to the hardlinks if it has more than one link, and set f.hardlink to
+// the first file with the same identity.
+func (h *hardlinks) add(info os.FileInfo, f *file) {
+	if linkCount(info) < 2 {
+		return
+	}
+	for _, link := range *h {
+		if os.SameFile(link.info, info) {
+			f.hardlink = link.file
+			return
+		}
+	}
+	*h = append(*h, linkedFile{info: info, file: f})
+}
+
+func (r *dirReader) newDirectory(path string, info os.FileInfo) (*directory, error) {
 	items := make(map[string]dirEntry)
 	children, err := os.ReadDir(path)
 	if err != nil {
@@ -89,35 +152,65 @@ func newDirectory(path string, info os.FileInfo) (*directory, error) {
 	}
 	for _, child := range children {
 		fullPath := filepath.Join(path, child.Name())
-		items[child.Name()], err = getTypedResource(fullPath, child)
+		items[child.Name()], err = r.getTypedResource(fullPath, child)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	res, err := r.newResource(path, info)
+	if err != nil {
+		return nil, err
+	}
 	return &directory{
-		resource:      newResourceFromInfo(info),
+		resource:      res,
 		items:         items,
 		filepathGlobs: make(map[string]*filePath),
 	}, nil
 }
 
-func getTypedResource(path string, entry os.DirEntry) (dirEntry, error) {
+func (r *dirReader) getTypedResource(path string, entry os.DirEntry) (dirEntry, error) {
 	info, err := entry.Info()
 	if err != nil {
 		return nil, err
 	}
-	switch {
+	switch mode := info.Mode(); {
 	case info.IsDir():
-		return newDirectory(path, info)
-	case info.Mode()&os.ModeSymlink != 0:
+		return r.newDirectory(path, info)
+	case mode&os.ModeSymlink != 0:
 		return newSymlink(path, info)
-	// TODO: devices, pipes?
+	case mode&(os.ModeNamedPipe|os.ModeSocket|os.ModeDevice) != 0:
+		return r.newSpecialFile(path, info, specialFileType(mode))
 	default:
-		return newFile(path, info)
+		f, err := r.newFile(path, info)
+		if err != nil {
+			return nil, err
+		}
+		r.links.add(info, f)
+		return f, nil
 	}
 }
 
+// newResource returns the resource for the file at path, including the
+// extended attributes of the file if r.readXattrs is true.
+func (r *dirReader) newResource(path string, info os.FileInfo) (resource, error) {
+	res := newResourceFromInfo(info)
+	if !r.readXattrs {
+		return res, nil
+	}
+	xattrs, err := readXattrs(path)
+	res.xattrs = xattrs
+	return res, err
+}
+
+func (r *dirReader) newSpecialFile(path string, info os.FileInfo, typ string) (*specialFile, error) {
+	res, err := r.newResource(path, info)
+	if err != nil {
+		return nil, err
+	}
+	return &specialFile{resource: res, typ: typ}, nil
+}
+
 func newSymlink(path string, info os.FileInfo) (*symlink, error) {
 	target, err := os.Readlink(path)
 	if err != nil {
@@ -129,14 +222,18 @@ func newSymlink(path string, info os.FileInfo) (*symlink, error) {
 	}, err
 }
 
-func newFile(path string, info os.FileInfo) (*file, error) {
+func (r *dirReader) newFile(path string, info os.FileInfo) (*file, error) {
+	res, err := r.newResource(path, info)
+	if err != nil {
+		return nil, err
+	}
 	// TODO: defer file opening to reduce number of open FDs?
 	readCloser, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	return &file{
-		resource: newResourceFromInfo(info),
+		resource: res,
 		content:  readCloser,
 	}, err
 }
@@ -0,0 +1,261 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// jsonEntry is the JSON representation of an entry in a Manifest. The keys of
+// maps are sorted by encoding/json, so the JSON is stable.
+type jsonEntry struct {
+	Type string `json:"type"`
+	// Mode is formatted by os.FileMode.String, or "any-mode".
+	Mode   string            `json:"mode"`
+	UID    uint32            `json:"uid"`
+	GID    uint32            `json:"gid"`
+	Xattrs map[string]string `json:"xattrs,omitempty"`
+	// Size and SHA256 are not set for a file that may have any content.
+	Size   *int   `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Target string `json:"target,omitempty"`
+	// Hardlink is the slash separated path of the file that this file is a
+	// hard link to, relative to the root of the manifest.
+	Hardlink string                `json:"hardlink,omitempty"`
+	Entries  map[string]*jsonEntry `json:"entries,omitempty"`
+}
+
+// MarshalJSON returns the manifest as a JSON document. The document includes
+// the type, mode, uid, gid, and extended attributes of each file, directory,
+// and symlink, the size and sha256 hash of the content of files, and the
+// target of symlinks and hard links.
+//
+// The JSON can be saved and loaded with [Manifest.UnmarshalJSON] to compare a
+// directory to a manifest that was created earlier. Manifests with files that
+// use [MatchFileContent] or [MatchFilesWithGlob] can not be marshaled.
+func (m Manifest) MarshalJSON() ([]byte, error) {
+	if m.root == nil {
+		return nil, fmt.Errorf("manifest is empty")
+	}
+	m2j := &manifestToJSON{paths: filePaths(m.root, "")}
+	root, err := m2j.directory(m.root, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+type manifestToJSON struct {
+	// paths are the paths of the files in the manifest, used to find the path
+	// of the target of a hard link.
+	paths map[*file]string
+}
+
+func (m *manifestToJSON) directory(dir *directory, dirPath string) (*jsonEntry, error) {
+	if len(dir.filepathGlobs) > 0 {
+		return nil, fmt.Errorf("directory %q: MatchFilesWithGlob can not be marshaled", dirPath)
+	}
+	entry := newJSONEntry(dir.Type(), dir.resource)
+	if len(dir.items) > 0 {
+		entry.Entries = make(map[string]*jsonEntry, len(dir.items))
+	}
+
+	for name, item := range dir.items {
+		var err error
+		entryPath := path.Join(dirPath, name)
+		switch typed := item.(type) {
+		case *directory:
+			entry.Entries[name], err = m.directory(typed, entryPath)
+		case *file:
+			entry.Entries[name], err = m.file(typed, entryPath)
+		case *symlink:
+			entry.Entries[name] = newJSONEntry(typed.Type(), typed.resource)
+			entry.Entries[name].Target = typed.target
+		case *specialFile:
+			entry.Entries[name] = newJSONEntry(typed.Type(), typed.resource)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func (m *manifestToJSON) file(f *file, filePath string) (*jsonEntry, error) {
+	entry := newJSONEntry(f.Type(), f.resource)
+	if f.hardlink != nil {
+		target, ok := m.paths[f.hardlink]
+		if !ok {
+			return nil, fmt.Errorf("file %q: target of hard link not found", filePath)
+		}
+		entry.Hardlink = target
+		return entry, nil
+	}
+	digest, err := f.contentDigest()
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("file %q: %w", filePath, err)
+	case digest != nil:
+		entry.SHA256 = digest.sha256
+		if digest.size >= 0 {
+			entry.Size = &digest.size
+		}
+	}
+	return entry, nil
+}
+
+func newJSONEntry(typ string, res resource) *jsonEntry {
+	return &jsonEntry{
+		Type:   typ,
+		Mode:   formatMode(res.mode),
+		UID:    res.uid,
+		GID:    res.gid,
+		Xattrs: res.xattrs,
+	}
+}
+
+// UnmarshalJSON sets m to the manifest in the JSON document created by
+// [Manifest.MarshalJSON]. The content of files is compared using the size and
+// sha256 hash of the content.
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	var root jsonEntry
+	if err := json.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Type != "directory" {
+		return fmt.Errorf("root of manifest must be a directory, not %q", root.Type)
+	}
+	j2m := &jsonToManifest{files: make(map[string]*file)}
+	dir, err := j2m.directory(&root, "")
+	if err != nil {
+		return err
+	}
+	for _, link := range j2m.hardlinks {
+		target, ok := j2m.files[link.target]
+		if !ok {
+			return fmt.Errorf("file %q: target of hard link %q not found", link.path, link.target)
+		}
+		link.file.hardlink = target
+	}
+	m.root = dir
+	return nil
+}
+
+type jsonToManifest struct {
+	// files are the files in the manifest, keyed by path.
+	files     map[string]*file
+	hardlinks []jsonHardlink
+}
+
+type jsonHardlink struct {
+	path   string
+	target string
+	file   *file
+}
+
+func (j *jsonToManifest) directory(entry *jsonEntry, dirPath string) (*directory, error) {
+	res, err := resourceFromJSON(entry, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := &directory{
+		resource:      res,
+		items:         make(map[string]dirEntry, len(entry.Entries)),
+		filepathGlobs: make(map[string]*filePath),
+	}
+	for name, child := range entry.Entries {
+		entryPath := path.Join(dirPath, name)
+		if child == nil {
+			return nil, fmt.Errorf("entry %q is null", entryPath)
+		}
+		res, err := resourceFromJSON(child, entryPath)
+		if err != nil {
+			return nil, err
+		}
+		switch child.Type {
+		case "directory":
+			dir.items[name], err = j.directory(child, entryPath)
+		case "file":
+			dir.items[name], err = j.file(child, res, entryPath)
+		case "symlink":
+			dir.items[name] = &symlink{resource: res, target: child.Target}
+		case "fifo", "socket", "device":
+			dir.items[name] = &specialFile{resource: res, typ: child.Type}
+		default:
+			err = fmt.Errorf("entry %q has an unknown type %q", entryPath, child.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+func (j *jsonToManifest) file(entry *jsonEntry, res resource, filePath string) (*file, error) {
+	f := &file{resource: res, content: anyFileContent}
+	switch {
+	case entry.Hardlink != "":
+		j.hardlinks = append(j.hardlinks, jsonHardlink{
+			path:   filePath,
+			target: entry.Hardlink,
+			file:   f,
+		})
+		return f, nil
+	case entry.SHA256 == "":
+		j.files[filePath] = f
+		return f, nil
+	}
+	f.digest = &contentDigest{size: -1, sha256: entry.SHA256}
+	if entry.Size != nil {
+		f.digest.size = *entry.Size
+	}
+	f.content = newBytesContent(nil)
+	j.files[filePath] = f
+	return f, nil
+}
+
+func resourceFromJSON(entry *jsonEntry, entryPath string) (resource, error) {
+	mode, err := parseFileMode(entry.Mode)
+	if err != nil {
+		return resource{}, fmt.Errorf("entry %q: %w", entryPath, err)
+	}
+	return resource{mode: mode, uid: entry.UID, gid: entry.GID, xattrs: entry.Xattrs}, nil
+}
+
+// modeTypeChars are the characters used by os.FileMode.String for each of the
+// mode bits, starting with the most significant bit.
+const modeTypeChars = "dalTLDpSugct?"
+
+// parseFileMode parses a mode formatted by os.FileMode.String.
+func parseFileMode(s string) (os.FileMode, error) {
+	if s == "any-mode" {
+		return anyFileMode, nil
+	}
+	const permChars = "rwxrwxrwx"
+	if len(s) < len(permChars)+1 {
+		return 0, fmt.Errorf("invalid file mode %q", s)
+	}
+	typ, perm := s[:len(s)-len(permChars)], s[len(s)-len(permChars):]
+
+	var mode os.FileMode
+	if typ != "-" {
+		for _, c := range typ {
+			i := strings.IndexRune(modeTypeChars, c)
+			if i < 0 {
+				return 0, fmt.Errorf("invalid file mode %q", s)
+			}
+			mode |= 1 << uint(32-1-i)
+		}
+	}
+	for i, c := range perm {
+		switch {
+		case c == rune(permChars[i]):
+			mode |= 1 << uint(len(permChars)-1-i)
+		case c != '-':
+			return 0, fmt.Errorf("invalid file mode %q", s)
+		}
+	}
+	return mode, nil
+}
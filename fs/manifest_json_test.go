@@ -0,0 +1,110 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+func TestManifestMarshalJSON(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "file mode is different on windows")
+	dir := NewDir(t, t.Name(),
+		WithMode(0755),
+		WithFile("a.txt", "hello", WithMode(0644)),
+		WithHardlink("b.txt", "a.txt"),
+		WithDir("sub", WithMode(0700)))
+
+	raw, err := json.Marshal(ManifestFromDir(t, dir.Path()))
+	assert.NilError(t, err)
+	expected := fmt.Sprintf(`{"type":"directory","mode":"drwxr-xr-x","uid":%[1]d,"gid":%[2]d,`+
+		`"entries":{`+
+		`"a.txt":{"type":"file","mode":"-rw-r--r--","uid":%[1]d,"gid":%[2]d,"size":5,`+
+		`"sha256":"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},`+
+		`"b.txt":{"type":"file","mode":"-rw-r--r--","uid":%[1]d,"gid":%[2]d,"hardlink":"a.txt"},`+
+		`"sub":{"type":"directory","mode":"drwx------","uid":%[1]d,"gid":%[2]d}}}`,
+		currentUID(), currentGID())
+	assert.Equal(t, string(raw), expected)
+}
+
+func TestManifestMarshalJSON_HardlinkBeforeTarget(t *testing.T) {
+	manifest := Expected(t,
+		WithDir("sub", WithFile("x", "content")),
+		WithHardlink("a", "sub/x"))
+
+	raw, err := json.Marshal(manifest)
+	assert.NilError(t, err)
+	var saved Manifest
+	assert.NilError(t, json.Unmarshal(raw, &saved))
+	assert.Equal(t, saved.root.items["a"].(*file).hardlink,
+		saved.root.items["sub"].(*directory).items["x"].(*file))
+}
+
+func TestManifestUnmarshalJSON(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("config", "one"),
+		WithSymlink("link", "config"),
+		WithDir("sub", WithFile("data", "", WithMode(0600))))
+
+	raw, err := json.Marshal(ManifestFromDir(t, dir.Path()))
+	assert.NilError(t, err)
+	var saved Manifest
+	assert.NilError(t, json.Unmarshal(raw, &saved))
+	assert.Assert(t, Equal(dir.Path(), saved))
+
+	again, err := json.Marshal(saved)
+	assert.NilError(t, err)
+	assert.Equal(t, string(again), string(raw))
+
+	assert.NilError(t, os.WriteFile(dir.Join("config"), []byte("two"), 0644))
+	result := Equal(dir.Path(), saved)()
+	assert.Assert(t, !result.Success())
+	assert.Assert(t, is.Contains(failureProblems(result), fmtExpected(`/config
+  content: expected 3 bytes sha256:7692c3ad3540bb803c020b3aee66cd8887123234ea0c6e7143c0add73ff431ed `+
+		`got 3 bytes sha256:3fc4ccfe745870e2c0d99f71f30ff0656c8dedd41cc1d7d3d376b0dbe685e2f3`)))
+}
+
+func TestManifestMarshalJSON_Unsupported(t *testing.T) {
+	manifest := Expected(t, WithFile("file", "", MatchFileContent(func([]byte) CompareResult {
+		return is.ResultSuccess
+	})))
+	_, err := json.Marshal(manifest)
+	assert.ErrorContains(t, err, `file "file": MatchFileContent can not be marshaled`)
+
+	manifest = Expected(t, MatchFilesWithGlob("*.go", MatchAnyFileContent))
+	_, err = json.Marshal(manifest)
+	assert.ErrorContains(t, err, `MatchFilesWithGlob can not be marshaled`)
+}
+
+func TestManifestUnmarshalJSON_AnyContent(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("file", "content"), WithFile("*.log", ""))
+	var manifest Manifest
+	err := json.Unmarshal([]byte(`{"type":"directory","mode":"any-mode","entries":{`+
+		`"file":{"type":"file","mode":"any-mode"},`+
+		`"*":{"type":"file","mode":"any-mode"}}}`), &manifest)
+	assert.NilError(t, err)
+	manifest.root.uid, manifest.root.gid = currentUID(), currentGID()
+	for _, entry := range manifest.root.items {
+		entry.(*file).uid, entry.(*file).gid = currentUID(), currentGID()
+	}
+	assert.Assert(t, Equal(dir.Path(), manifest))
+}
+
+func TestParseFileMode(t *testing.T) {
+	for _, mode := range []os.FileMode{
+		0, 0644, 0755 | os.ModeDir, 0777 | os.ModeSymlink, 0600 | os.ModeNamedPipe,
+		0755 | os.ModeSetuid | os.ModeSetgid, 0777 | os.ModeDir | os.ModeSticky, anyFileMode,
+	} {
+		actual, err := parseFileMode(formatMode(mode))
+		assert.NilError(t, err)
+		assert.Equal(t, actual, mode, formatMode(mode))
+	}
+
+	_, err := parseFileMode("-rw-r--r-x-")
+	assert.ErrorContains(t, err, `invalid file mode "-rw-r--r-x-"`)
+}
@@ -87,6 +87,10 @@ func TestSymlinks(t *testing.T) {
 
 var cmpManifest = cmp.Options{
 	cmp.AllowUnexported(Manifest{}, resource{}, file{}, symlink{}, directory{}),
+	cmp.FilterPath(func(path cmp.Path) bool {
+		field, ok := path.Last().(cmp.StructField)
+		return ok && field.Name() == "mtime"
+	}, cmp.Ignore()),
 	cmp.Comparer(func(x, y io.ReadCloser) bool {
 		if x == nil || y == nil {
 			return x == y
@@ -23,12 +23,22 @@ func init() {
 func newResourceFromInfo(info os.FileInfo) resource {
 	statT := info.Sys().(*syscall.Stat_t)
 	return resource{
-		mode: info.Mode(),
-		uid:  statT.Uid,
-		gid:  statT.Gid,
+		mode:  info.Mode(),
+		uid:   statT.Uid,
+		gid:   statT.Gid,
+		mtime: info.ModTime(),
 	}
 }
 
+// linkCount returns the number of hard links to the file.
+func linkCount(info os.FileInfo) uint64 {
+	return uint64(info.Sys().(*syscall.Stat_t).Nlink)
+}
+
+func mkfifo(path string) error {
+	return syscall.Mkfifo(path, defaultFileMode)
+}
+
 func (p *filePath) SetMode(mode os.FileMode) {
 	p.file.mode = mode
 }
@@ -1,6 +1,9 @@
 package fs
 
-import "os"
+import (
+	"errors"
+	"os"
+)
 
 const (
 	defaultRootDirMode = os.ModeDir | 0777
@@ -8,7 +11,17 @@ const (
 )
 
 func newResourceFromInfo(info os.FileInfo) resource {
-	return resource{mode: info.Mode()}
+	return resource{mode: info.Mode(), mtime: info.ModTime()}
+}
+
+// linkCount returns the number of hard links to the file. The number of links
+// is not available on windows.
+func linkCount(os.FileInfo) uint64 {
+	return 1
+}
+
+func mkfifo(string) error {
+	return errors.New("named pipes are not supported on windows")
 }
 
 func (p *filePath) SetMode(mode os.FileMode) {
@@ -1,12 +1,10 @@
 package fs
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"gotest.tools/v3/assert"
@@ -38,11 +36,19 @@ type manifestDirectory interface {
 	AddDirectory(path string, ops ...PathOp) error
 }
 
+type manifestXattrs interface {
+	SetXattr(name, value string)
+}
+
+type manifestMtime interface {
+	AddMtimeMatcher(match mtimeMatcher)
+}
+
 // WithContent writes content to a file at [Path]
 func WithContent(content string) PathOp {
 	return func(path Path) error {
 		if m, ok := path.(manifestFile); ok {
-			m.SetContent(io.NopCloser(strings.NewReader(content)))
+			m.SetContent(newBytesContent([]byte(content)))
 			return nil
 		}
 		return os.WriteFile(path.Path(), []byte(content), defaultFileMode)
@@ -53,7 +59,7 @@ func WithContent(content string) PathOp {
 func WithBytes(raw []byte) PathOp {
 	return func(path Path) error {
 		if m, ok := path.(manifestFile); ok {
-			m.SetContent(io.NopCloser(bytes.NewReader(raw)))
+			m.SetContent(newBytesContent(raw))
 			return nil
 		}
 		return os.WriteFile(path.Path(), raw, defaultFileMode)
@@ -64,7 +70,11 @@ func WithBytes(raw []byte) PathOp {
 func WithReaderContent(r io.Reader) PathOp {
 	return func(path Path) error {
 		if m, ok := path.(manifestFile); ok {
-			m.SetContent(io.NopCloser(r))
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			m.SetContent(newBytesContent(content))
 			return nil
 		}
 		f, err := os.OpenFile(path.Path(), os.O_WRONLY, defaultFileMode)
@@ -255,23 +265,61 @@ func WithSymlink(path, target string) PathOp {
 // WithHardlink creates a link in the directory which links to target.
 // Target must be a path relative to the directory.
 //
+// When used with a [Manifest], the file at path is expected to be a hard link
+// to the file at target. The target must be added to the manifest before the
+// link, and must be in the same directory as the link, or a subdirectory.
+// The content and properties of the link are not compared, because they are
+// the same as the target.
+//
 // Note: the argument order is the inverse of [os.Link] to be consistent with
 // the other functions in this package.
 func WithHardlink(path, target string) PathOp {
 	return func(root Path) error {
-		if _, ok := root.(manifestDirectory); ok {
-			return fmt.Errorf("WithHardlink not implemented for manifests")
+		if v, ok := root.(*directoryPath); ok {
+			return v.AddHardlink(path, target)
 		}
 		return os.Link(filepath.Join(root.Path(), target), filepath.Join(root.Path(), path))
 	}
 }
 
+// WithXattr sets the extended attribute with name to value on the file or
+// directory at [Path]. Extended attributes are only supported on Linux, and
+// most filesystems only allow unprivileged users to set attributes in the
+// user namespace, ex: user.comment.
+//
+// When used with a [Manifest], the resource is expected to have the attribute.
+// Only the attributes set with WithXattr are compared.
+func WithXattr(name, value string) PathOp {
+	return func(path Path) error {
+		if m, ok := path.(manifestXattrs); ok {
+			m.SetXattr(name, value)
+			return nil
+		}
+		return setXattr(path.Path(), name, value)
+	}
+}
+
+// WithFIFO creates a named pipe in the directory at path. Named pipes are not
+// supported on Windows.
+func WithFIFO(name string, ops ...PathOp) PathOp {
+	return func(path Path) error {
+		if m, ok := path.(*directoryPath); ok {
+			return m.AddFIFO(name, ops...)
+		}
+		fullpath := filepath.Join(path.Path(), filepath.FromSlash(name))
+		if err := mkfifo(fullpath); err != nil {
+			return err
+		}
+		return applyPathOps(&File{path: fullpath}, ops)
+	}
+}
+
 // WithTimestamps sets the access and modification times of the file system object
-// at path.
+// at path. Use [MatchMtime] to compare the modification time with a [Manifest].
 func WithTimestamps(atime, mtime time.Time) PathOp {
 	return func(root Path) error {
 		if _, ok := root.(manifestDirectory); ok {
-			return fmt.Errorf("WithTimestamp not implemented for manifests")
+			return fmt.Errorf("WithTimestamp not implemented for manifests, use MatchMtime")
 		}
 		return os.Chtimes(root.Path(), atime, mtime)
 	}
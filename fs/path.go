@@ -2,8 +2,12 @@ package fs
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -35,6 +39,39 @@ func (p *filePath) SetGID(gid uint32) {
 	p.file.gid = gid
 }
 
+func (p *filePath) SetXattr(name, value string) {
+	setResourceXattr(&p.file.resource, name, value)
+}
+
+func (p *filePath) AddMtimeMatcher(match mtimeMatcher) {
+	p.file.matchMtime = append(p.file.matchMtime, match)
+}
+
+type specialFilePath struct {
+	resourcePath
+	file *specialFile
+}
+
+func (p *specialFilePath) SetMode(mode os.FileMode) {
+	p.file.mode = mode | os.ModeNamedPipe
+}
+
+func (p *specialFilePath) SetUID(uid uint32) {
+	p.file.uid = uid
+}
+
+func (p *specialFilePath) SetGID(gid uint32) {
+	p.file.gid = gid
+}
+
+func (p *specialFilePath) SetXattr(name, value string) {
+	setResourceXattr(&p.file.resource, name, value)
+}
+
+func (p *specialFilePath) AddMtimeMatcher(match mtimeMatcher) {
+	p.file.matchMtime = append(p.file.matchMtime, match)
+}
+
 type directoryPath struct {
 	resourcePath
 	directory *directory
@@ -48,6 +85,21 @@ func (p *directoryPath) SetGID(gid uint32) {
 	p.directory.gid = gid
 }
 
+func (p *directoryPath) SetXattr(name, value string) {
+	setResourceXattr(&p.directory.resource, name, value)
+}
+
+func (p *directoryPath) AddMtimeMatcher(match mtimeMatcher) {
+	p.directory.matchMtime = append(p.directory.matchMtime, match)
+}
+
+func setResourceXattr(r *resource, name, value string) {
+	if r.xattrs == nil {
+		r.xattrs = make(map[string]string)
+	}
+	r.xattrs[name] = value
+}
+
 func (p *directoryPath) AddSymlink(path, target string) error {
 	p.directory.items[path] = &symlink{
 		resource: newResource(defaultSymlinkMode),
@@ -63,6 +115,40 @@ func (p *directoryPath) AddFile(path string, ops ...PathOp) error {
 	return applyPathOps(exp, ops)
 }
 
+func (p *directoryPath) AddFIFO(path string, ops ...PathOp) error {
+	newFIFO := &specialFile{resource: newResource(os.ModeNamedPipe | defaultFileMode), typ: "fifo"}
+	p.directory.items[path] = newFIFO
+	return applyPathOps(&specialFilePath{file: newFIFO}, ops)
+}
+
+// AddHardlink adds a file at path that is expected to be a hard link to the
+// file at target. The target must already be in the manifest.
+func (p *directoryPath) AddHardlink(path, target string) error {
+	dir := p.directory
+	elems := strings.Split(filepath.ToSlash(target), "/")
+	for _, elem := range elems[:len(elems)-1] {
+		next, ok := dir.items[elem].(*directory)
+		if !ok {
+			return fmt.Errorf("hardlink target %s must be a file in the manifest", target)
+		}
+		dir = next
+	}
+	targetFile, ok := dir.items[elems[len(elems)-1]].(*file)
+	if !ok {
+		return fmt.Errorf("hardlink target %s must be a file in the manifest", target)
+	}
+	if targetFile.hardlink != nil {
+		targetFile = targetFile.hardlink
+	}
+	// The content and properties of the link are compared using the target.
+	p.directory.items[path] = &file{
+		resource: newResource(anyFileMode),
+		content:  anyFileContent,
+		hardlink: targetFile,
+	}
+	return nil
+}
+
 func (p *directoryPath) AddGlobFiles(glob string, ops ...PathOp) error {
 	newFile := &file{resource: newResource(0)}
 	newFilePath := &filePath{file: newFile}
@@ -196,3 +282,60 @@ func MatchAnyFileMode(path Path) error {
 	}
 	return nil
 }
+
+// MatchMtime is a [PathOp] that updates a [Manifest] so that the resource at
+// path must have a modification time equal to mtime.
+//
+// The modification time is not compared unless one of MatchMtime,
+// [MatchMtimeWithin], or [MatchMtimeNewerThan] is used. When more than one of
+// them is used, the modification time must match all of them.
+func MatchMtime(mtime time.Time) PathOp {
+	return func(path Path) error {
+		if m, ok := path.(manifestMtime); ok {
+			m.AddMtimeMatcher(func(actual time.Time) (bool, string) {
+				return actual.Equal(mtime), mtime.Format(time.RFC3339Nano)
+			})
+		}
+		return nil
+	}
+}
+
+// MatchMtimeWithin is a [PathOp] that updates a [Manifest] so that the
+// resource at path must have a modification time within d of the time when
+// the manifest is compared. MatchMtimeWithin can be used to check that a file
+// was recently modified.
+func MatchMtimeWithin(d time.Duration) PathOp {
+	return func(path Path) error {
+		if m, ok := path.(manifestMtime); ok {
+			m.AddMtimeMatcher(func(actual time.Time) (bool, string) {
+				now := time.Now()
+				diff := now.Sub(actual)
+				if diff < 0 {
+					diff = -diff
+				}
+				return diff <= d, fmt.Sprintf("within %s of %s", d, now.Format(time.RFC3339Nano))
+			})
+		}
+		return nil
+	}
+}
+
+// MatchMtimeNewerThan is a [PathOp] that updates a [Manifest] so that the
+// resource at path must have a modification time after the modification time
+// of the file at reference. reference is a path on the filesystem, and is
+// read when the manifest is compared.
+func MatchMtimeNewerThan(reference string) PathOp {
+	return func(path Path) error {
+		if m, ok := path.(manifestMtime); ok {
+			m.AddMtimeMatcher(func(actual time.Time) (bool, string) {
+				info, err := os.Stat(reference)
+				if err != nil {
+					return false, fmt.Sprintf("newer than %s (%s)", reference, err)
+				}
+				return actual.After(info.ModTime()), fmt.Sprintf("newer than %s (%s)",
+					reference, info.ModTime().Format(time.RFC3339Nano))
+			})
+		}
+		return nil
+	}
+}
@@ -0,0 +1,229 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// String returns the files, directories, and symlinks in the manifest as a
+// tree, similar to the output of `tree -p`. Each entry includes the mode,
+// the uid and gid, and for files the size and a prefix of the sha256 hash of
+// the content.
+//
+//	[drwxr-xr-x 1000:1000]  .
+//	├── [-rw-r--r-- 1000:1000 5 sha256:2cf24dba5fb0]  hello.txt
+//	├── [Lrwxrwxrwx 1000:1000]  link -> hello.txt
+//	└── [drwxr-xr-x 1000:1000]  sub
+//	    └── [-rw-r--r-- 1000:1000 0 sha256:e3b0c44298fc]  empty
+func (m Manifest) String() string {
+	if m.root == nil {
+		return "<empty manifest>\n"
+	}
+	r := &treeRenderer{buf: new(strings.Builder), paths: filePaths(m.root, "")}
+	fmt.Fprintf(r.buf, "[%s]  .\n", r.properties(m.root))
+	r.directory(m.root, "", "")
+	return r.buf.String()
+}
+
+type treeRenderer struct {
+	buf *strings.Builder
+	// paths are the paths of the files in the manifest, used to find the path
+	// of the target of a hard link.
+	paths map[*file]string
+}
+
+// filePaths returns the slash separated paths of the files in dir, relative
+// to the root of the manifest.
+func filePaths(dir *directory, dirPath string) map[*file]string {
+	paths := make(map[*file]string)
+	var walk func(dir *directory, dirPath string)
+	walk = func(dir *directory, dirPath string) {
+		for name, entry := range dir.items {
+			switch typed := entry.(type) {
+			case *directory:
+				walk(typed, path.Join(dirPath, name))
+			case *file:
+				paths[typed] = path.Join(dirPath, name)
+			}
+		}
+	}
+	walk(dir, dirPath)
+	return paths
+}
+
+func (r *treeRenderer) directory(dir *directory, dirPath string, indent string) {
+	names := make([]string, 0, len(dir.items))
+	for name := range dir.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		branch, nextIndent := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, nextIndent = "└── ", "    "
+		}
+		entryPath := path.Join(dirPath, name)
+		entry := dir.items[name]
+		fmt.Fprintf(r.buf, "%s%s[%s]  %s%s\n",
+			indent, branch, r.properties(entry), name, r.suffix(entry))
+		if sub, ok := entry.(*directory); ok {
+			r.directory(sub, entryPath, indent+nextIndent)
+		}
+	}
+}
+
+func (r *treeRenderer) properties(entry dirEntry) string {
+	res := entryResource(entry)
+	props := []string{formatMode(res.mode), fmt.Sprintf("%d:%d", res.uid, res.gid)}
+	if f, ok := entry.(*file); ok && f.hardlink == nil {
+		props = append(props, formatContent(f))
+	}
+	return strings.Join(props, " ")
+}
+
+func (r *treeRenderer) suffix(entry dirEntry) string {
+	switch typed := entry.(type) {
+	case *symlink:
+		return " -> " + typed.target
+	case *file:
+		if typed.hardlink == nil {
+			return ""
+		}
+		if target, ok := r.paths[typed.hardlink]; ok {
+			return " => " + target
+		}
+		return " => (hard link)"
+	}
+	return ""
+}
+
+func entryResource(entry dirEntry) resource {
+	switch typed := entry.(type) {
+	case *directory:
+		return typed.resource
+	case *file:
+		return typed.resource
+	case *symlink:
+		return typed.resource
+	case *specialFile:
+		return typed.resource
+	}
+	return resource{}
+}
+
+func formatMode(mode os.FileMode) string {
+	if mode == anyFileMode {
+		return "any-mode"
+	}
+	return mode.String()
+}
+
+// formatContent returns the size and a prefix of the sha256 hash of the
+// content of f.
+func formatContent(f *file) string {
+	if f.compareContentFunc != nil {
+		return "content-matcher"
+	}
+	digest, err := f.contentDigest()
+	switch {
+	case err != nil:
+		return fmt.Sprintf("content-error(%s)", err)
+	case digest == nil:
+		return "any-content"
+	}
+	return digest.short()
+}
+
+// contentDigest is the size and sha256 hash of the content of a file.
+type contentDigest struct {
+	// size is the size of the content, or -1 if the size is not compared.
+	size   int
+	sha256 string
+}
+
+func newContentDigest(content []byte) *contentDigest {
+	return &contentDigest{size: len(content), sha256: fmt.Sprintf("%x", sha256.Sum256(content))}
+}
+
+func (d *contentDigest) match(content []byte) bool {
+	actual := newContentDigest(content)
+	return actual.sha256 == d.sha256 && (d.size < 0 || d.size == actual.size)
+}
+
+func (d *contentDigest) String() string {
+	if d.size < 0 {
+		return "sha256:" + d.sha256
+	}
+	return fmt.Sprintf("%d bytes sha256:%s", d.size, d.sha256)
+}
+
+// short returns the size and a prefix of the hash.
+func (d *contentDigest) short() string {
+	hash := d.sha256
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	if d.size < 0 {
+		return "sha256:" + hash
+	}
+	return fmt.Sprintf("%d sha256:%s", d.size, hash)
+}
+
+// contentDigest returns the digest of the content of f, or nil if f may have
+// any content. An error is returned if f uses MatchFileContent.
+func (f *file) contentDigest() (*contentDigest, error) {
+	switch {
+	case f.digest != nil:
+		return f.digest, nil
+	case f.compareContentFunc != nil:
+		return nil, fmt.Errorf("MatchFileContent can not be marshaled")
+	case f.content == nil || f.content == anyFileContent:
+		return nil, nil
+	}
+	content, err := f.peekContent()
+	if err != nil {
+		return nil, err
+	}
+	return newContentDigest(content), nil
+}
+
+// closeLinkContent closes the content of a file which is a hard link. The
+// content of a hard link is never read, because it is the same as the content
+// of the target.
+func (f *file) closeLinkContent() {
+	if fh, ok := f.content.(*os.File); ok {
+		_ = fh.Close()
+	}
+}
+
+// peekContent reads the content of the file without consuming or closing it,
+// so that the manifest can still be compared after it is rendered.
+func (f *file) peekContent() ([]byte, error) {
+	r, ok := f.content.(io.ReaderAt)
+	if !ok {
+		return nil, fmt.Errorf("content can not be read without consuming it")
+	}
+	return io.ReadAll(io.NewSectionReader(r, 0, math.MaxInt64))
+}
+
+// bytesContent is the content of a file in a manifest that is held in memory.
+// It implements io.ReaderAt, so that it can be read by peekContent.
+type bytesContent struct {
+	*bytes.Reader
+}
+
+func newBytesContent(content []byte) bytesContent {
+	return bytesContent{Reader: bytes.NewReader(content)}
+}
+
+func (bytesContent) Close() error {
+	return nil
+}
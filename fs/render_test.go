@@ -0,0 +1,121 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+func TestManifestString(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "file mode is different on windows")
+	dir := NewDir(t, t.Name(),
+		WithMode(0755),
+		WithFile("hello.txt", "hello", WithMode(0644)),
+		WithSymlink("link", "hello.txt"),
+		WithHardlink("same.txt", "hello.txt"),
+		WithDir("sub", WithMode(0700),
+			WithFile("empty", "", WithMode(0600))))
+
+	owner := fmt.Sprintf("%d:%d", currentUID(), currentGID())
+	expected := fmt.Sprintf(`[drwxr-xr-x %[1]s]  .
+├── [-rw-r--r-- %[1]s 5 sha256:2cf24dba5fb0]  hello.txt
+├── [Lrwxrwxrwx %[1]s]  link -> %[2]s
+├── [-rw-r--r-- %[1]s]  same.txt => hello.txt
+└── [drwx------ %[1]s]  sub
+    └── [-rw------- %[1]s 0 sha256:e3b0c44298fc]  empty
+`, owner, dir.Join("hello.txt"))
+	assert.Equal(t, ManifestFromDir(t, dir.Path()).String(), expected)
+}
+
+func TestManifestString_Expected(t *testing.T) {
+	manifest := Expected(t,
+		WithMode(0755),
+		WithFile("any", "", MatchAnyFileContent, MatchAnyFileMode),
+		WithFile("matcher", "", MatchFileContent(func([]byte) CompareResult {
+			return is.ResultSuccess
+		})))
+
+	owner := fmt.Sprintf("%d:%d", currentUID(), currentGID())
+	expected := fmt.Sprintf(`[drwxr-xr-x %[1]s]  .
+├── [any-mode %[1]s any-content]  any
+└── [-rw-r--r-- %[1]s content-matcher]  matcher
+`, owner)
+	assert.Equal(t, manifest.String(), expected)
+}
+
+func TestEqualActualTree(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "file mode is different on windows")
+	dir := NewDir(t, t.Name(), WithMode(0755), WithFile("file", "content", WithMode(0644)))
+
+	result := Equal(dir.Path(), Expected(t, WithMode(0755)))()
+	assert.Assert(t, !result.Success())
+	owner := fmt.Sprintf("%d:%d", currentUID(), currentGID())
+	expected := fmtExpected(`directory %s does not match expected:
+/
+  file: unexpected file
+
+actual:
+[drwxr-xr-x %[2]s]  .
+└── [-rw-r--r-- %[2]s 7 sha256:ed7002b439e9]  file
+`, dir.Path(), owner)
+	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+}
+
+func TestEqualActualTree_Truncated(t *testing.T) {
+	var ops []PathOp
+	for i := 0; i < maxActualTreeLines+10; i++ {
+		ops = append(ops, WithFile(fmt.Sprintf("file%03d", i), ""))
+	}
+	dir := NewDir(t, t.Name(), ops...)
+
+	result := Equal(dir.Path(), Expected(t))()
+	assert.Assert(t, !result.Success())
+	msg := result.(cmpFailure).FailureMessage()
+	assert.Assert(t, is.Contains(msg, "]  file048\n... (11 more entries)\n"))
+	assert.Assert(t, !strings.Contains(msg, "]  file049"))
+}
+
+func TestManifestString_HardlinkBeforeTarget(t *testing.T) {
+	manifest := Expected(t,
+		WithDir("sub", WithFile("x", "content")),
+		WithHardlink("a", "sub/x"))
+
+	out := manifest.String()
+	assert.Assert(t, is.Contains(out, "]  a => sub/x\n"), out)
+}
+
+func TestManifestString_DoesNotConsumeContent(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("file", "content"),
+		WithHardlink("link", "file"))
+
+	expected := Expected(t,
+		WithFile("file", "content"),
+		WithHardlink("link", "file"))
+	_ = expected.String()
+	_ = expected.String()
+	assert.Assert(t, Equal(dir.Path(), expected))
+}
+
+func TestFormatActualTree_ClosesFiles(t *testing.T) {
+	skip.If(t, runtime.GOOS != "linux", "counts the open files in /proc")
+	dir := NewDir(t, t.Name(),
+		WithFile("file", "content"),
+		WithHardlink("one", "file"),
+		WithHardlink("two", "file"))
+
+	openFiles := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		assert.NilError(t, err)
+		return len(entries)
+	}
+	before := openFiles()
+	assert.Assert(t, is.Contains(formatActualTree(dir.Path()), "two => file"))
+	assert.Equal(t, openFiles(), before)
+}
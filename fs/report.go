@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/internal/format"
@@ -22,19 +24,80 @@ import (
 // Equal is a [cmp.Comparison] which can be used with [gotest.tools/v3/assert.Assert].
 func Equal(path string, expected Manifest) cmp.Comparison {
 	return func() cmp.Result {
-		actual, err := manifestFromDir(path)
+		actual, err := manifestFromDir(path, hasXattrs(expected.root))
 		if err != nil {
 			return cmp.ResultFromError(err)
 		}
 		failures := eqDirectory(string(os.PathSeparator), expected.root, actual.root)
+		failures = append(failures, eqHardlinks(path, expected.root)...)
 		if len(failures) == 0 {
 			return cmp.ResultSuccess
 		}
 		msg := fmt.Sprintf("directory %s does not match expected:\n", path)
-		return cmp.ResultFailure(msg + formatFailures(failures))
+		return cmp.ResultFailure(msg + formatFailures(failures) + formatActualTree(path))
 	}
 }
 
+// maxActualTreeLines is the maximum number of lines of the actual tree that
+// are included in the failure message of Equal.
+const maxActualTreeLines = 50
+
+// formatActualTree returns the tree of the directory at path, to include in
+// the failure message. The content of the files in the manifest used for the
+// comparison has already been read and closed, so the directory is read again. Large
+// trees are truncated to maxActualTreeLines.
+func formatActualTree(path string) string {
+	actual, err := manifestFromDir(path, false)
+	if err != nil {
+		return ""
+	}
+	defer closeContent(actual.root)
+	tree := actual.String()
+	lines := strings.SplitAfter(tree, "\n")
+	// the last element is empty, because the tree ends with a newline
+	if remaining := len(lines) - 1 - maxActualTreeLines; remaining > 0 {
+		tree = strings.Join(lines[:maxActualTreeLines], "") +
+			fmt.Sprintf("... (%d more entries)\n", remaining)
+	}
+	return "\nactual:\n" + tree
+}
+
+// closeContent closes the content of the files in dir.
+func closeContent(dir *directory) {
+	for _, entry := range dir.items {
+		switch typed := entry.(type) {
+		case *directory:
+			closeContent(typed)
+		case *file:
+			_ = typed.content.Close()
+		}
+	}
+}
+
+// hasXattrs returns true if dir, or any of the entries in dir, expect
+// extended attributes.
+func hasXattrs(dir *directory) bool {
+	if dir == nil {
+		return false
+	}
+	if len(dir.xattrs) > 0 {
+		return true
+	}
+	for _, entry := range dir.items {
+		switch typed := entry.(type) {
+		case *directory:
+			if hasXattrs(typed) {
+				return true
+			}
+		default:
+			if len(entryResource(entry).xattrs) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type failure struct {
 	path     string
 	problems []problem
@@ -42,6 +105,15 @@ type failure struct {
 
 type problem string
 
+// addProblem appends a failure with problem p to failures. path is relative to
+// the root of the directory that was compared.
+func addProblem(failures []failure, path string, p problem) []failure {
+	return append(failures, failure{
+		path:     filepath.Join(string(os.PathSeparator), path),
+		problems: []problem{p},
+	})
+}
+
 func notEqual(property string, x, y interface{}) problem {
 	return problem(fmt.Sprintf("%s: expected %s got %s", property, x, y))
 }
@@ -65,14 +137,45 @@ func eqResource(x, y resource) []problem {
 	if x.mode != anyFileMode && x.mode != y.mode {
 		p = append(p, notEqual("mode", x.mode, y.mode))
 	}
+	for _, match := range x.matchMtime {
+		if ok, expected := match(y.mtime); !ok {
+			p = append(p, notEqual("mtime", expected, y.mtime.Format(time.RFC3339Nano)))
+		}
+	}
+	for _, name := range sortedStrings(x.xattrs) {
+		value, ok := y.xattrs[name]
+		switch {
+		case !ok:
+			p = append(p, notEqual("xattr "+name, strconv.Quote(x.xattrs[name]), "no value"))
+		case value != x.xattrs[name]:
+			p = append(p, notEqual("xattr "+name, strconv.Quote(x.xattrs[name]), strconv.Quote(value)))
+		}
+	}
 	return p
 }
 
+func sortedStrings(items map[string]string) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func removeCarriageReturn(in []byte) []byte {
 	return bytes.Replace(in, []byte("\r\n"), []byte("\n"), -1)
 }
 
 func eqFile(x, y *file) []problem {
+	if x.hardlink != nil {
+		// hard links are compared by eqHardlinks
+		x.closeLinkContent()
+		if y.hardlink != nil {
+			y.closeLinkContent()
+		}
+		return nil
+	}
 	p := eqResource(x.resource, y.resource)
 
 	switch {
@@ -101,10 +204,22 @@ func eqFile(x, y *file) []problem {
 		return p
 	}
 
+	if x.digest != nil {
+		if !x.digest.match(yContent) {
+			p = append(p, notEqual("content", x.digest, newContentDigest(yContent)))
+		}
+		return p
+	}
+
 	if x.compareContentFunc != nil {
 		r := x.compareContentFunc(yContent)
-		if !r.Success() {
-			p = append(p, existenceProblem("content", r.FailureMessage()))
+		switch typed := r.(type) {
+		case problemResult:
+			p = append(p, problem(typed))
+		default:
+			if !r.Success() {
+				p = append(p, existenceProblem("content", r.FailureMessage()))
+			}
 		}
 		return p
 	}
@@ -230,12 +345,55 @@ func eqEntry(path string, x, y dirEntry) []failure {
 		return resp(eqFile(typed, y.(*file)))
 	case *symlink:
 		return resp(eqSymlink(typed, y.(*symlink)))
+	case *specialFile:
+		return resp(eqResource(typed.resource, y.(*specialFile).resource))
 	case *directory:
 		return eqDirectory(path, typed, y.(*directory))
 	}
 	return nil
 }
 
+// eqHardlinks compares the files in the directory at root to the hard links
+// expected by the manifest directory x. Missing files are reported by
+// eqDirectory, so they are ignored.
+func eqHardlinks(root string, x *directory) []failure {
+	paths := make(map[*file]string)
+	var links []*file
+	var walk func(path string, dir *directory)
+	walk = func(path string, dir *directory) {
+		for name, entry := range dir.items {
+			switch typed := entry.(type) {
+			case *file:
+				paths[typed] = filepath.Join(path, name)
+				if typed.hardlink != nil {
+					links = append(links, typed)
+				}
+			case *directory:
+				walk(filepath.Join(path, name), typed)
+			}
+		}
+	}
+	walk(string(os.PathSeparator), x)
+
+	var failures []failure
+	for _, link := range links {
+		linkPath, targetPath := paths[link], paths[link.hardlink]
+		linkInfo, err := os.Lstat(filepath.Join(root, linkPath))
+		if err != nil {
+			continue
+		}
+		targetInfo, err := os.Lstat(filepath.Join(root, targetPath))
+		if err != nil || os.SameFile(linkInfo, targetInfo) {
+			continue
+		}
+		failures = append(failures, failure{
+			path:     linkPath,
+			problems: []problem{problem("expected a hard link to " + targetPath)},
+		})
+	}
+	return failures
+}
+
 type globMatch struct {
 	match    bool
 	failures []failure
@@ -261,13 +419,15 @@ func matchGlob(name string, yEntry dirEntry, globs map[string]*filePath) globMat
 }
 
 func formatFailures(failures []failure) string {
-	sort.Slice(failures, func(i, j int) bool {
+	sort.SliceStable(failures, func(i, j int) bool {
 		return failures[i].path < failures[j].path
 	})
 
 	buf := new(bytes.Buffer)
-	for _, failure := range failures {
-		buf.WriteString(failure.path + "\n")
+	for i, failure := range failures {
+		if i == 0 || failures[i-1].path != failure.path {
+			buf.WriteString(failure.path + "\n")
+		}
 		for _, problem := range failure.problems {
 			buf.WriteString("  " + string(problem) + "\n")
 		}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -18,7 +20,7 @@ func TestEqualMissingRoot(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		expected = "CreateFile /bogus/path/does/not/exist"
 	}
-	assert.Assert(t, is.Contains(result.(cmpFailure).FailureMessage(), expected))
+	assert.Assert(t, is.Contains(failureProblems(result), expected))
 }
 
 func TestEqualModeMismatch(t *testing.T) {
@@ -37,7 +39,7 @@ func TestEqualModeMismatch(t *testing.T) {
   mode: expected drwxrwxrwx got dr-xr-xr-x
 `, dir.Path())
 	}
-	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	assert.Equal(t, failureProblems(result), expected)
 }
 
 func TestEqualRootIsAFile(t *testing.T) {
@@ -47,7 +49,7 @@ func TestEqualRootIsAFile(t *testing.T) {
 	result := Equal(file.Path(), Expected(t))()
 	assert.Assert(t, !result.Success())
 	expected := fmt.Sprintf("path %s must be a directory", file.Path())
-	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	assert.Equal(t, failureProblems(result), expected)
 }
 
 func TestEqualSuccess(t *testing.T) {
@@ -70,7 +72,7 @@ func TestEqualDirectoryHasWithExtraFiles(t *testing.T) {
   file1: expected file to exist
   extra1: unexpected file
 `, dir.Path())
-	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	assert.Equal(t, failureProblems(result), expected)
 }
 
 func fmtExpected(format string, args ...interface{}) string {
@@ -106,7 +108,7 @@ func TestEqualWithFileContent(t *testing.T) {
      line2
      line3
 `, dir.Path())
-	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	assert.Equal(t, failureProblems(result), expected)
 }
 
 func TestEqualWithMatchContentIgnoreCarriageReturn(t *testing.T) {
@@ -160,13 +162,23 @@ func TestEqualManyFailures(t *testing.T) {
     -not the
      same in both
 `, dir.Path())
-	assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	assert.Equal(t, failureProblems(result), expected)
 }
 
 type cmpFailure interface {
 	FailureMessage() string
 }
 
+// failureProblems returns the failure message from Equal without the tree of
+// the actual directory. The tree is tested by TestEqualActualTree.
+func failureProblems(result is.Result) string {
+	msg := result.(cmpFailure).FailureMessage()
+	if i := strings.Index(msg, "\nactual:\n"); i >= 0 {
+		return msg[:i]
+	}
+	return msg
+}
+
 func TestMatchAnyFileMode(t *testing.T) {
 	dir := NewDir(t, t.Name(),
 		WithFile("data", "content",
@@ -205,7 +217,7 @@ func TestMatchFileContent(t *testing.T) {
 /data
   content: data content differs from expected
 `, dir.Path())
-		assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+		assert.Equal(t, failureProblems(result), expected)
 	})
 }
 
@@ -236,7 +248,7 @@ func TestMatchExtraFilesGlob(t *testing.T) {
 conf.yml
   mode: expected -rwx------ got -rw-------
 `, dir.Path())
-		assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+		assert.Equal(t, failureProblems(result), expected)
 	})
 
 	t.Run("matching partial glob", func(t *testing.T) {
@@ -248,7 +260,7 @@ conf.yml
 /
   conf.yml: unexpected file
 `, dir.Path())
-		assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+		assert.Equal(t, failureProblems(result), expected)
 	})
 
 	t.Run("invalid glob", func(t *testing.T) {
@@ -268,6 +280,71 @@ conf.yml
 t.go
   failed to match glob pattern: syntax error in pattern
 `, dir.Path())
-		assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+		assert.Equal(t, failureProblems(result), expected)
+	})
+}
+
+func TestMatchMtime(t *testing.T) {
+	stamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	dir := NewDir(t, t.Name(),
+		WithFile("old", "content", WithTimestamps(stamp, stamp)),
+		WithFile("new", "content"))
+	defer dir.Remove()
+
+	t.Run("mtime matches", func(t *testing.T) {
+		manifest := Expected(t,
+			WithFile("old", "content", MatchMtime(stamp)),
+			WithFile("new", "content",
+				MatchMtimeWithin(time.Hour),
+				MatchMtimeNewerThan(dir.Join("old"))))
+		assert.Assert(t, Equal(dir.Path(), manifest))
+	})
+
+	t.Run("mtime does not match", func(t *testing.T) {
+		manifest := Expected(t,
+			WithFile("old", "content", MatchMtime(stamp.Add(time.Second))),
+			WithFile("new", "content"))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, !result.Success())
+
+		expected := fmtExpected(`directory %s does not match expected:
+/old
+  mtime: expected 2020-01-02T03:04:06Z got 2020-01-02T03:04:05Z
+`, dir.Path())
+		assert.Equal(t, failureProblems(result), expected)
+	})
+
+	t.Run("mtime not within duration", func(t *testing.T) {
+		manifest := Expected(t,
+			WithFile("old", "content", MatchMtimeWithin(time.Hour)),
+			WithFile("new", "content"))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, !result.Success())
+		assert.Assert(t, is.Contains(failureProblems(result),
+			"mtime: expected within 1h0m0s of "))
+	})
+
+	t.Run("all matchers must match", func(t *testing.T) {
+		manifest := Expected(t,
+			WithFile("old", "content",
+				MatchMtimeNewerThan(dir.Join("new")),
+				MatchMtimeWithin(time.Hour),
+				MatchMtime(stamp)),
+			WithFile("new", "content"))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, !result.Success())
+		problems := failureProblems(result)
+		assert.Assert(t, is.Contains(problems, "mtime: expected newer than "+dir.Join("new")))
+		assert.Assert(t, is.Contains(problems, "mtime: expected within 1h0m0s of "))
+	})
+
+	t.Run("mtime not newer than", func(t *testing.T) {
+		manifest := Expected(t,
+			WithFile("old", "content", MatchMtimeNewerThan(dir.Join("new"))),
+			WithFile("new", "content"))
+		result := Equal(dir.Path(), manifest)()
+		assert.Assert(t, !result.Success())
+		assert.Assert(t, is.Contains(failureProblems(result),
+			"mtime: expected newer than "+dir.Join("new")))
 	})
 }
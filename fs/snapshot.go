@@ -0,0 +1,290 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+// DirSnapshot is the state of a directory at the time it was created by
+// [Snapshot]. It is used to find the changes made to the directory after the
+// snapshot.
+type DirSnapshot struct {
+	t       assert.TestingT
+	path    string
+	entries map[string]snapshotEntry
+}
+
+type snapshotEntry struct {
+	resource
+	typ     string
+	mtime   time.Time
+	content [sha256.Size]byte
+	target  string
+}
+
+// Snapshot records the state of the directory at path, and all the files,
+// directories, and symlinks in the directory. Use [DirSnapshot.Changes] or
+// [ExpectChanges] to find the changes made to the directory after the
+// snapshot.
+func Snapshot(t assert.TestingT, path string) *DirSnapshot {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	entries, err := snapshotDir(path)
+	assert.NilError(t, err)
+	return &DirSnapshot{t: t, path: path, entries: entries}
+}
+
+// Property is a property of a file, directory, or symlink that was changed.
+type Property string
+
+// The properties that are compared by [DirSnapshot.Changes].
+const (
+	PropertyType    Property = "type"
+	PropertyContent Property = "content"
+	PropertyMode    Property = "mode"
+	PropertyOwner   Property = "owner"
+	PropertyTarget  Property = "target"
+	PropertyMtime   Property = "mtime"
+)
+
+// ChangeKind is the kind of a [Change].
+type ChangeKind string
+
+// The kinds of changes that are reported by [DirSnapshot.Changes].
+const (
+	ChangeCreated  ChangeKind = "created"
+	ChangeDeleted  ChangeKind = "deleted"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a change made to a file, directory, or symlink.
+type Change struct {
+	// Path is the path of the file, relative to the directory of the snapshot.
+	Path string
+	Kind ChangeKind
+	// Properties that were changed when Kind is ChangeModified.
+	Properties []Property
+}
+
+func (c Change) String() string {
+	if len(c.Properties) == 0 {
+		return string(c.Kind)
+	}
+	props := make([]string, 0, len(c.Properties))
+	for _, prop := range c.Properties {
+		props = append(props, string(prop))
+	}
+	return fmt.Sprintf("%s (%s)", c.Kind, strings.Join(props, ", "))
+}
+
+// Created returns a [Change] for a file, directory, or symlink at path that
+// was created. path is a slash separated path relative to the snapshot
+// directory.
+func Created(path string) Change {
+	return Change{Path: filepath.FromSlash(path), Kind: ChangeCreated}
+}
+
+// Deleted returns a [Change] for a file, directory, or symlink at path that
+// was deleted. path is a slash separated path relative to the snapshot
+// directory.
+func Deleted(path string) Change {
+	return Change{Path: filepath.FromSlash(path), Kind: ChangeDeleted}
+}
+
+// Modified returns a [Change] for a file, directory, or symlink at path that
+// was modified. path is a slash separated path relative to the snapshot
+// directory. When used with [ExpectChanges] the change matches a modification
+// of at least the properties, or a modification of any properties if none are
+// given.
+func Modified(path string, properties ...Property) Change {
+	return Change{Path: filepath.FromSlash(path), Kind: ChangeModified, Properties: properties}
+}
+
+// Changes returns the changes made to the directory since the snapshot was
+// created, sorted by path. The mtime of directories is not compared, because
+// it changes every time an entry is created or deleted in the directory.
+func (s *DirSnapshot) Changes() []Change {
+	if ht, ok := s.t.(helperT); ok {
+		ht.Helper()
+	}
+	changes, err := s.changes()
+	assert.NilError(s.t, err)
+	return changes
+}
+
+func (s *DirSnapshot) changes() ([]Change, error) {
+	current, err := snapshotDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, before := range s.entries {
+		after, ok := current[path]
+		if !ok {
+			changes = append(changes, Change{Path: path, Kind: ChangeDeleted})
+			continue
+		}
+		if props := changedProperties(before, after); len(props) > 0 {
+			changes = append(changes, Change{Path: path, Kind: ChangeModified, Properties: props})
+		}
+	}
+	for path := range current {
+		if _, ok := s.entries[path]; !ok {
+			changes = append(changes, Change{Path: path, Kind: ChangeCreated})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func changedProperties(x, y snapshotEntry) []Property {
+	if x.typ != y.typ {
+		return []Property{PropertyType}
+	}
+	var props []Property
+	if x.content != y.content {
+		props = append(props, PropertyContent)
+	}
+	if x.mode != y.mode {
+		props = append(props, PropertyMode)
+	}
+	if x.uid != y.uid || x.gid != y.gid {
+		props = append(props, PropertyOwner)
+	}
+	if x.target != y.target {
+		props = append(props, PropertyTarget)
+	}
+	if x.typ != "directory" && !x.mtime.Equal(y.mtime) {
+		props = append(props, PropertyMtime)
+	}
+	return props
+}
+
+// ExpectChanges compares the changes made to the directory since the snapshot
+// was created to the expected changes, in any order. Every change to the
+// directory must match one of the expected changes. See [Created], [Deleted],
+// and [Modified].
+//
+// ExpectChanges is a [cmp.Comparison] which can be used with
+// [gotest.tools/v3/assert.Assert].
+func ExpectChanges(snapshot *DirSnapshot, expected ...Change) cmp.Comparison {
+	return func() cmp.Result {
+		changes, err := snapshot.changes()
+		if err != nil {
+			return cmp.ResultFromError(err)
+		}
+		actual := make(map[string]Change, len(changes))
+		for _, change := range changes {
+			actual[change.Path] = change
+		}
+
+		var failures []failure
+		for _, exp := range expected {
+			change, ok := actual[exp.Path]
+			delete(actual, exp.Path)
+			switch {
+			case !ok:
+				failures = addProblem(failures, exp.Path,
+					problem(fmt.Sprintf("expected %s, got no change", exp)))
+			case !matchChange(exp, change):
+				failures = addProblem(failures, exp.Path, notEqual("change", exp, change))
+			}
+		}
+		for path, change := range actual {
+			failures = addProblem(failures, path,
+				problem(fmt.Sprintf("unexpected change: %s", change)))
+		}
+
+		if len(failures) == 0 {
+			return cmp.ResultSuccess
+		}
+		msg := fmt.Sprintf("directory %s does not have the expected changes:\n", snapshot.path)
+		return cmp.ResultFailure(msg + formatFailures(failures))
+	}
+}
+
+func matchChange(expected, actual Change) bool {
+	if expected.Kind != actual.Kind {
+		return false
+	}
+	for _, prop := range expected.Properties {
+		if !hasProperty(actual.Properties, prop) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasProperty(props []Property, prop Property) bool {
+	for _, p := range props {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotDir returns the state of every entry in the directory at root,
+// keyed by the path relative to root. The root directory has the path ".".
+func snapshotDir(root string) (map[string]snapshotEntry, error) {
+	entries := make(map[string]snapshotEntry)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entry, err := newSnapshotEntry(path, info)
+		if err != nil {
+			return err
+		}
+		entries[rel] = entry
+		return nil
+	})
+	return entries, err
+}
+
+func newSnapshotEntry(path string, info os.FileInfo) (snapshotEntry, error) {
+	entry := snapshotEntry{resource: newResourceFromInfo(info), mtime: info.ModTime()}
+	switch {
+	case info.IsDir():
+		entry.typ = "directory"
+	case info.Mode()&os.ModeSymlink != 0:
+		entry.typ = "symlink"
+		target, err := os.Readlink(path)
+		if err != nil {
+			return entry, err
+		}
+		entry.target = target
+	case info.Mode().IsRegular():
+		entry.typ = "file"
+		f, err := os.Open(path)
+		if err != nil {
+			return entry, err
+		}
+		defer f.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, f); err != nil {
+			return entry, err
+		}
+		copy(entry.content[:], hash.Sum(nil))
+	default:
+		entry.typ = info.Mode().Type().String()
+	}
+	return entry, nil
+}
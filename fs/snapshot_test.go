@@ -0,0 +1,105 @@
+package fs
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/skip"
+)
+
+func TestSnapshot_Changes(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "symlinks require admin on windows")
+	dir := NewDir(t, t.Name(),
+		WithFile("unchanged", "same"),
+		WithFile("content", "before"),
+		WithFile("mode", "", WithMode(0600)),
+		WithFile("deleted", ""),
+		WithSymlink("link", "content"),
+		WithDir("sub"))
+	snapshot := Snapshot(t, dir.Path())
+
+	past := time.Now().Add(-time.Hour)
+	Apply(t, dir,
+		WithFile("content", "after"),
+		WithFile("sub/created", ""))
+	Apply(t, &File{path: dir.Join("content")}, WithTimestamps(past, past))
+	assert.NilError(t, os.Chmod(dir.Join("mode"), 0644))
+	assert.NilError(t, os.Remove(dir.Join("deleted")))
+	assert.NilError(t, os.Remove(dir.Join("link")))
+	assert.NilError(t, os.Symlink(dir.Join("unchanged"), dir.Join("link")))
+
+	changes := snapshot.Changes()
+	assert.Equal(t, len(changes), 5)
+	link := changes[2]
+	assert.Equal(t, link.Path, "link")
+	assert.Assert(t, hasProperty(link.Properties, PropertyTarget), link)
+	// the mtime of the new symlink may be the same as the old symlink
+	changes[2].Properties = []Property{PropertyTarget}
+
+	assert.DeepEqual(t, changes, []Change{
+		{Path: "content", Kind: ChangeModified, Properties: []Property{PropertyContent, PropertyMtime}},
+		{Path: "deleted", Kind: ChangeDeleted},
+		{Path: "link", Kind: ChangeModified, Properties: []Property{PropertyTarget}},
+		{Path: "mode", Kind: ChangeModified, Properties: []Property{PropertyMode}},
+		{Path: "sub/created", Kind: ChangeCreated},
+	})
+}
+
+func TestExpectChanges(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("modified", "before"),
+		WithFile("deleted", ""),
+		WithFile("unchanged", ""))
+	snapshot := Snapshot(t, dir.Path())
+
+	Apply(t, dir,
+		WithFile("modified", "after"),
+		WithFile("created", ""))
+	assert.NilError(t, os.Remove(dir.Join("deleted")))
+
+	t.Run("success", func(t *testing.T) {
+		assert.Assert(t, ExpectChanges(snapshot,
+			Created("created"),
+			Deleted("deleted"),
+			Modified("modified", PropertyContent)))
+		assert.Assert(t, ExpectChanges(snapshot,
+			Modified("modified"),
+			Deleted("deleted"),
+			Created("created")))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		result := ExpectChanges(snapshot,
+			Deleted("created"),
+			Modified("modified", PropertyMode),
+			Modified("unchanged"))()
+		assert.Assert(t, !result.Success())
+
+		expected := fmtExpected(`directory %s does not have the expected changes:
+/created
+  change: expected deleted got created
+/deleted
+  unexpected change: deleted
+/modified
+  change: expected modified (mode) got modified (content%s)
+/unchanged
+  expected modified, got no change
+`, dir.Path(), mtimeChange(snapshot, "modified"))
+		assert.Equal(t, result.(cmpFailure).FailureMessage(), expected)
+	})
+}
+
+// mtimeChange returns the mtime property in the format used by Change.String,
+// if the mtime of the file at path was changed. The mtime may not change when
+// the file is written quickly after it is created.
+func mtimeChange(snapshot *DirSnapshot, path string) string {
+	for _, change := range snapshot.Changes() {
+		if change.Path == path && hasProperty(change.Properties, PropertyMtime) {
+			return ", mtime"
+		}
+	}
+	return ""
+}
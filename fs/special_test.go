@@ -0,0 +1,92 @@
+package fs
+
+import (
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/skip"
+)
+
+func TestWithFIFO(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "named pipes are not supported on windows")
+	dir := NewDir(t, t.Name(), WithFIFO("pipe"), WithFIFO("private", WithMode(0600)))
+
+	manifest := ManifestFromDir(t, dir.Path())
+	pipe, ok := manifest.root.items["pipe"].(*specialFile)
+	assert.Assert(t, ok, "expected a fifo, got %T", manifest.root.items["pipe"])
+	assert.Equal(t, pipe.Type(), "fifo")
+
+	assert.Assert(t, Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithFIFO("pipe"),
+		WithFIFO("private", WithMode(0600)))))
+
+	result := Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithFile("pipe", ""),
+		WithFIFO("private")))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/
+  pipe: expected file got fifo
+/private
+  mode: expected prw-r--r-- got prw-------
+`, dir.Path()))
+}
+
+func TestWithHardlink_Manifest(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("a", "content"),
+		WithHardlink("b", "a"),
+		WithFile("c", "content"),
+		WithDir("sub", WithFile("d", "other")),
+		WithHardlink("e", "sub/d"))
+
+	assert.Assert(t, Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithFile("a", "content"),
+		WithHardlink("b", "a"),
+		WithFile("c", "content"),
+		WithDir("sub", WithFile("d", "other")),
+		WithHardlink("e", "sub/d"))))
+
+	result := Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithFile("a", "content"),
+		WithFile("b", "content"),
+		WithHardlink("c", "a"),
+		WithDir("sub", WithFile("d", "other")),
+		WithHardlink("e", "sub/d")))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/c
+  expected a hard link to /a
+`, dir.Path()))
+
+	t.Run("missing target", func(t *testing.T) {
+		err := WithHardlink("b", "a")(&directoryPath{directory: newDirectoryWithDefaults()})
+		assert.Error(t, err, "hardlink target a must be a file in the manifest")
+	})
+}
+
+func TestManifestFromDir_Hardlinks(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "the number of links is not available on windows")
+	dir := NewDir(t, t.Name(),
+		WithFile("a", "content"),
+		WithHardlink("b", "a"),
+		WithFile("c", "content"))
+	manifest := ManifestFromDir(t, dir.Path())
+	a := manifest.root.items["a"].(*file)
+	assert.Assert(t, a.hardlink == nil)
+	assert.Assert(t, manifest.root.items["b"].(*file).hardlink == a)
+	assert.Assert(t, manifest.root.items["c"].(*file).hardlink == nil)
+
+	copied := NewDir(t, t.Name(), FromDir(dir.Path()), WithMode(0700))
+	result := Equal(copied.Path(), ManifestFromDir(t, dir.Path()))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/b
+  expected a hard link to /a
+`, copied.Path()))
+}
@@ -0,0 +1,175 @@
+package fs
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gotest.tools/v3/assert"
+)
+
+// FromTxtar is a [PathOp] that creates the files in a txtar archive in the
+// directory at path. The name of each file in the archive is a slash separated
+// path relative to the directory. Directories are created for each element of
+// the path. A name that ends with a slash creates an empty directory. The
+// comment at the start of the archive is ignored.
+//
+// When used with a [Manifest], FromTxtar adds the files and directories to the
+// manifest, with the same mode as [WithFile] and [WithDir].
+//
+// The archive uses the txtar format from golang.org/x/tools/txtar. Each file
+// starts with a marker line "-- name --", followed by the content of the file.
+// A newline is added to the content of a file if it does not end with one.
+// Example:
+//
+//	dir := fs.NewDir(t, "fixture", fs.FromTxtar(`
+//	-- config.yaml --
+//	name: example
+//	-- data/one.txt --
+//	one
+//	-- empty/ --
+//	`))
+func FromTxtar(archive string) PathOp {
+	return func(path Path) error {
+		ops, err := txtarOps(parseTxtar(archive))
+		if err != nil {
+			return err
+		}
+		return applyPathOps(path, ops)
+	}
+}
+
+// ExpectedFromTxtar returns a [Manifest] with the files and directories in a
+// txtar archive. The ops are applied to the root directory of the manifest
+// after the files from the archive. See [FromTxtar] for the format of the
+// archive.
+func ExpectedFromTxtar(t assert.TestingT, archive string, ops ...PathOp) Manifest {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	return Expected(t, append([]PathOp{FromTxtar(archive)}, ops...)...)
+}
+
+type txtarFile struct {
+	name string
+	data string
+}
+
+// parseTxtar returns the files in a txtar archive. The comment before the
+// first file is ignored.
+func parseTxtar(archive string) []txtarFile {
+	var files []txtarFile
+	var data *strings.Builder
+	for _, line := range strings.SplitAfter(archive, "\n") {
+		if name, ok := txtarMarker(line); ok {
+			if data != nil {
+				files[len(files)-1].data = fixNewline(data.String())
+			}
+			files = append(files, txtarFile{name: name})
+			data = new(strings.Builder)
+			continue
+		}
+		if data != nil {
+			data.WriteString(line)
+		}
+	}
+	if data != nil {
+		files[len(files)-1].data = fixNewline(data.String())
+	}
+	return files
+}
+
+// txtarMarker returns the name of the file if line is a file marker.
+func txtarMarker(line string) (string, bool) {
+	line = strings.TrimSuffix(line, "\n")
+	const start, end = "-- ", " --"
+	if !strings.HasPrefix(line, start) || !strings.HasSuffix(line, end) ||
+		len(line) < len(start)+len(end) {
+		return "", false
+	}
+	name := strings.TrimSpace(line[len(start) : len(line)-len(end)])
+	return name, name != ""
+}
+
+// fixNewline adds a newline to the end of data if it is not empty and does not
+// end with a newline.
+func fixNewline(data string) string {
+	if data == "" || strings.HasSuffix(data, "\n") {
+		return data
+	}
+	return data + "\n"
+}
+
+type txtarDir struct {
+	files map[string]string
+	dirs  map[string]*txtarDir
+}
+
+func newTxtarDir() *txtarDir {
+	return &txtarDir{files: make(map[string]string), dirs: make(map[string]*txtarDir)}
+}
+
+// txtarOps returns the ops which create the files in the archive. The files are
+// grouped by directory, so that each directory is created by a single op.
+func txtarOps(files []txtarFile) ([]PathOp, error) {
+	root := newTxtarDir()
+	for _, f := range files {
+		name := strings.TrimSuffix(f.name, "/")
+		if name == "" || path.IsAbs(name) || path.Clean(name) != name ||
+			name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid file name in txtar archive: %q", f.name)
+		}
+
+		dir := root
+		elems := strings.Split(name, "/")
+		isDir := strings.HasSuffix(f.name, "/")
+		if !isDir {
+			elems = elems[:len(elems)-1]
+		}
+		for _, elem := range elems {
+			if _, ok := dir.files[elem]; ok {
+				return nil, fmt.Errorf("txtar archive has a file and a directory named %q", f.name)
+			}
+			if _, ok := dir.dirs[elem]; !ok {
+				dir.dirs[elem] = newTxtarDir()
+			}
+			dir = dir.dirs[elem]
+		}
+		if isDir {
+			continue
+		}
+
+		base := path.Base(name)
+		if _, ok := dir.files[base]; ok {
+			return nil, fmt.Errorf("duplicate file in txtar archive: %q", f.name)
+		}
+		if _, ok := dir.dirs[base]; ok {
+			return nil, fmt.Errorf("txtar archive has a file and a directory named %q", f.name)
+		}
+		dir.files[base] = f.data
+	}
+	return root.ops(), nil
+}
+
+func (d *txtarDir) ops() []PathOp {
+	fileNames := make([]string, 0, len(d.files))
+	for name := range d.files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+	dirNames := make([]string, 0, len(d.dirs))
+	for name := range d.dirs {
+		dirNames = append(dirNames, name)
+	}
+	sort.Strings(dirNames)
+
+	ops := make([]PathOp, 0, len(fileNames)+len(dirNames))
+	for _, name := range fileNames {
+		ops = append(ops, WithFile(name, d.files[name]))
+	}
+	for _, name := range dirNames {
+		ops = append(ops, WithDir(name, d.dirs[name].ops()...))
+	}
+	return ops
+}
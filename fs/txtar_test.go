@@ -0,0 +1,115 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+const txtarFixture = `This comment is ignored.
+-- config.yaml --
+name: example
+-- data/one.txt --
+one
+-- data/nested/two.txt --
+two
+-- empty/ --
+`
+
+func TestFromTxtar(t *testing.T) {
+	dir := NewDir(t, t.Name(), FromTxtar(txtarFixture))
+
+	expected := Expected(t,
+		WithMode(0700),
+		WithFile("config.yaml", "name: example\n"),
+		WithDir("data",
+			WithFile("one.txt", "one\n"),
+			WithDir("nested", WithFile("two.txt", "two\n"))),
+		WithDir("empty"))
+	assert.Assert(t, Equal(dir.Path(), expected))
+}
+
+func TestExpectedFromTxtar(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("config.yaml", "name: example\n"),
+		WithDir("data",
+			WithFile("one.txt", "one\n"),
+			WithDir("nested", WithFile("two.txt", "two\n"))),
+		WithDir("empty"))
+
+	assert.Assert(t, Equal(dir.Path(), ExpectedFromTxtar(t, txtarFixture, WithMode(0700))))
+
+	expected := ExpectedFromTxtar(t, `
+-- config.yaml --
+name: other
+`, WithMode(0700))
+	result := Equal(dir.Path(), expected)()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/
+  data: unexpected directory
+  empty: unexpected directory
+/config.yaml
+  content:
+    --- expected
+    +++ actual
+    @@ -1,2 +1,2 @@
+    -name: other
+    +name: example
+     
+`, dir.Path()))
+}
+
+func TestFromTxtar_InvalidArchive(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		archive  string
+		expected string
+	}{
+		{
+			name:     "absolute path",
+			archive:  "-- /etc/passwd --\n",
+			expected: `invalid file name in txtar archive: "/etc/passwd"`,
+		},
+		{
+			name:     "parent directory",
+			archive:  "-- ../file --\n",
+			expected: `invalid file name in txtar archive: "../file"`,
+		},
+		{
+			name:     "duplicate file",
+			archive:  "-- a/file --\n-- a/file --\n",
+			expected: `duplicate file in txtar archive: "a/file"`,
+		},
+		{
+			name:     "file and directory",
+			archive:  "-- a --\n-- a/file --\n",
+			expected: `txtar archive has a file and a directory named "a/file"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := NewDir(t, "txtar")
+			err := FromTxtar(tc.archive)(dir)
+			assert.Error(t, err, tc.expected)
+		})
+	}
+}
+
+func TestParseTxtar(t *testing.T) {
+	files := parseTxtar(`comment
+-- a.txt --
+one
+-- not a marker
+--  --
+-- b.txt --
+-- c.txt --
+no newline`)
+	assert.DeepEqual(t, files, []txtarFile{
+		{name: "a.txt", data: "one\n-- not a marker\n--  --\n"},
+		{name: "b.txt", data: ""},
+		{name: "c.txt", data: "no newline\n"},
+	}, cmp.AllowUnexported(txtarFile{}))
+
+	assert.Assert(t, parseTxtar("only a comment\n") == nil)
+}
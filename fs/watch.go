@@ -0,0 +1,265 @@
+package fs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/internal/cleanup"
+)
+
+// EventOp is the operation of an [Event].
+type EventOp string
+
+// The operations recorded by a [Watcher].
+const (
+	EventCreate EventOp = "create"
+	EventWrite  EventOp = "write"
+	EventRename EventOp = "rename"
+	EventRemove EventOp = "remove"
+)
+
+// Event is an operation on a file, directory, or symlink in a directory
+// watched by a [Watcher].
+type Event struct {
+	Op EventOp
+	// Path is the path of the file, relative to the watched directory. For
+	// EventRename it is the path before the rename.
+	Path string
+	// NewPath is the path of the file after the rename, when Op is EventRename.
+	NewPath string
+}
+
+func (e Event) String() string {
+	if e.Op == EventRename {
+		return fmt.Sprintf("%s %s -> %s", e.Op, e.Path, e.NewPath)
+	}
+	return fmt.Sprintf("%s %s", e.Op, e.Path)
+}
+
+// CreateEvent returns an [Event] for a file, directory, or symlink at path
+// that was created. path is a slash separated path relative to the watched
+// directory.
+func CreateEvent(path string) Event {
+	return Event{Op: EventCreate, Path: filepath.FromSlash(path)}
+}
+
+// WriteEvent returns an [Event] for a file at path that was written. path is a
+// slash separated path relative to the watched directory.
+func WriteEvent(path string) Event {
+	return Event{Op: EventWrite, Path: filepath.FromSlash(path)}
+}
+
+// RenameEvent returns an [Event] for a file, directory, or symlink that was
+// renamed from oldPath to newPath. The paths are slash separated paths
+// relative to the watched directory.
+func RenameEvent(oldPath, newPath string) Event {
+	return Event{
+		Op:      EventRename,
+		Path:    filepath.FromSlash(oldPath),
+		NewPath: filepath.FromSlash(newPath),
+	}
+}
+
+// RemoveEvent returns an [Event] for a file, directory, or symlink at path
+// that was removed. path is a slash separated path relative to the watched
+// directory.
+func RemoveEvent(path string) Event {
+	return Event{Op: EventRemove, Path: filepath.FromSlash(path)}
+}
+
+// Watcher records the operations on the files in a directory. Use [Watch] to
+// create a Watcher.
+type Watcher struct {
+	t    assert.TestingT
+	path string
+
+	mu      sync.Mutex
+	inotify *inotify
+	events  []Event
+}
+
+// Watch starts recording the create, write, rename, and remove operations on
+// the files, directories, and symlinks in the directory at path, and in all of
+// its subdirectories. The watch is removed when the test ends. Use
+// [Watcher.Events], [ExpectEvents], or [ExpectEventsUnordered] to check the
+// operations.
+//
+// Consecutive writes to the same file are recorded as a single event. A file
+// that is renamed into the directory is recorded as created, and a file that
+// is renamed out of the directory is recorded as removed. A new subdirectory is
+// watched when the events are read, so the entries created in it before then
+// are recorded as created, and writes to those entries are not recorded.
+//
+// Watch uses inotify, and is only supported on Linux.
+func Watch(t assert.TestingT, path string) *Watcher {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	in, err := newInotify(path)
+	assert.NilError(t, err)
+	w := &Watcher{t: t, path: path, inotify: in}
+	cleanup.Cleanup(t, w.Close)
+	return w
+}
+
+// Events returns the events recorded since the watch started, or since the
+// last call to [Watcher.Reset], in the order they happened.
+func (w *Watcher) Events() []Event {
+	if ht, ok := w.t.(helperT); ok {
+		ht.Helper()
+	}
+	events, err := w.read()
+	assert.NilError(w.t, err)
+	return events
+}
+
+// Reset removes all the recorded events. Use Reset to ignore the events from
+// the setup of a test.
+func (w *Watcher) Reset() {
+	if ht, ok := w.t.(helperT); ok {
+		ht.Helper()
+	}
+	_, err := w.read()
+	assert.NilError(w.t, err)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = nil
+}
+
+// Close stops recording events. Close is called automatically when the test
+// ends.
+func (w *Watcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.inotify == nil {
+		return
+	}
+	_ = w.inotify.close()
+	w.inotify = nil
+}
+
+func (w *Watcher) read() ([]Event, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.inotify != nil {
+		events, err := w.inotify.read(w.events)
+		if err != nil {
+			return nil, err
+		}
+		w.events = events
+	}
+	return append([]Event(nil), w.events...), nil
+}
+
+// appendEvent appends the event to events, unless it is a write to the same
+// file as the previous event.
+func appendEvent(events []Event, event Event) []Event {
+	if n := len(events); n > 0 && event.Op == EventWrite && events[n-1] == event {
+		return events
+	}
+	return append(events, event)
+}
+
+// ExpectEvents compares the events recorded by the watcher to the expected
+// events, in order. See [CreateEvent], [WriteEvent], [RenameEvent], and
+// [RemoveEvent].
+//
+// ExpectEvents is a [cmp.Comparison] which can be used with
+// [gotest.tools/v3/assert.Assert].
+func ExpectEvents(w *Watcher, expected ...Event) cmp.Comparison {
+	return func() cmp.Result {
+		actual, err := w.read()
+		if err != nil {
+			return cmp.ResultFromError(err)
+		}
+
+		var failures []failure
+		for i := 0; i < len(expected) || i < len(actual); i++ {
+			switch {
+			case i >= len(actual):
+				failures = addProblem(failures, expected[i].Path, problem(fmt.Sprintf(
+					"event %d: expected %s, got no event", i+1, formatEventOp(expected[i]))))
+			case i >= len(expected):
+				failures = addProblem(failures, actual[i].Path, problem(fmt.Sprintf(
+					"event %d: unexpected %s", i+1, formatEventOp(actual[i]))))
+			case expected[i] != actual[i]:
+				failures = addProblem(failures, expected[i].Path, problem(fmt.Sprintf(
+					"event %d: expected %s got %s", i+1, expected[i], actual[i])))
+			}
+		}
+		if len(failures) == 0 {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailure(eventsFailureMessage(w.path, failures, actual))
+	}
+}
+
+// ExpectEventsUnordered compares the events recorded by the watcher to the
+// expected events, in any order. Every recorded event must match one of the
+// expected events.
+//
+// ExpectEventsUnordered is a [cmp.Comparison] which can be used with
+// [gotest.tools/v3/assert.Assert].
+func ExpectEventsUnordered(w *Watcher, expected ...Event) cmp.Comparison {
+	return func() cmp.Result {
+		actual, err := w.read()
+		if err != nil {
+			return cmp.ResultFromError(err)
+		}
+
+		remaining := append([]Event(nil), actual...)
+		var failures []failure
+		for _, exp := range expected {
+			if i := indexEvent(remaining, exp); i >= 0 {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				continue
+			}
+			failures = addProblem(failures, exp.Path,
+				problem(fmt.Sprintf("expected %s, got no event", formatEventOp(exp))))
+		}
+		for _, event := range remaining {
+			failures = addProblem(failures, event.Path,
+				problem(fmt.Sprintf("unexpected event: %s", formatEventOp(event))))
+		}
+		if len(failures) == 0 {
+			return cmp.ResultSuccess
+		}
+		return cmp.ResultFailure(eventsFailureMessage(w.path, failures, actual))
+	}
+}
+
+func indexEvent(events []Event, event Event) int {
+	for i, e := range events {
+		if e == event {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatEventOp returns the operation of the event, and the new path of a
+// rename.
+func formatEventOp(event Event) string {
+	if event.Op == EventRename {
+		return fmt.Sprintf("%s -> %s", event.Op, event.NewPath)
+	}
+	return string(event.Op)
+}
+
+func eventsFailureMessage(path string, failures []failure, actual []Event) string {
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "directory %s does not have the expected events:\n", path)
+	buf.WriteString(formatFailures(failures))
+	buf.WriteString("\nrecorded events:\n")
+	if len(actual) == 0 {
+		buf.WriteString("  (none)\n")
+	}
+	for i, event := range actual {
+		fmt.Fprintf(buf, "  %d. %s\n", i+1, event)
+	}
+	return buf.String()
+}
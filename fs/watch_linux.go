@@ -0,0 +1,198 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotify reads the events for a directory tree from an inotify file
+// descriptor. The file descriptor is non-blocking, so read returns the events
+// that are queued, and never waits for new events.
+type inotify struct {
+	fd int
+	// paths are the paths of the watched directories, relative to the root,
+	// keyed by watch descriptor.
+	paths map[int]string
+	root  string
+	// moves are the moves that have not been matched to a destination yet,
+	// keyed by cookie. They are kept between reads, because the two events of
+	// a move may be returned by different reads.
+	moves map[uint32]pendingMove
+}
+
+// pendingMove is a move from a watched directory, which is recorded as a remove
+// until the destination of the move is found.
+type pendingMove struct {
+	// index of the remove event in the recorded events.
+	index int
+	path  string
+}
+
+func newInotify(root string) (*inotify, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	in := &inotify{
+		fd:    fd,
+		paths: make(map[int]string),
+		root:  root,
+		moves: make(map[uint32]pendingMove),
+	}
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		return in.addWatch(rel)
+	})
+	if err != nil {
+		_ = in.close()
+		return nil, err
+	}
+	return in, nil
+}
+
+func (in *inotify) addWatch(rel string) error {
+	wd, err := syscall.InotifyAddWatch(in.fd, filepath.Join(in.root, rel), inotifyMask)
+	if err != nil {
+		return &os.PathError{Op: "inotify_add_watch", Path: filepath.Join(in.root, rel), Err: err}
+	}
+	if rel == "." {
+		rel = ""
+	}
+	in.paths[wd] = rel
+	return nil
+}
+
+func (in *inotify) close() error {
+	return syscall.Close(in.fd)
+}
+
+// read appends the events that are queued to events, which are the events
+// recorded by earlier reads. A move from one watched directory to another is
+// recorded as a single rename event, by replacing the remove event recorded for
+// the source of the move.
+func (in *inotify) read(events []Event) ([]Event, error) {
+	var buf [syscall.SizeofInotifyEvent * 4096]byte
+	for {
+		n, err := syscall.Read(in.fd, buf[:])
+		switch {
+		case errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR):
+			return events, nil
+		case err != nil:
+			return nil, os.NewSyscallError("read", err)
+		case n < syscall.SizeofInotifyEvent:
+			return nil, errors.New("short read from inotify")
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[nameStart:nameStart+int(raw.Len)], "\x00"))
+			offset = nameStart + int(raw.Len)
+
+			if raw.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				return nil, errors.New("inotify event queue overflowed")
+			}
+			dir, ok := in.paths[int(raw.Wd)]
+			if !ok {
+				continue
+			}
+			if raw.Mask&syscall.IN_IGNORED != 0 {
+				delete(in.paths, int(raw.Wd))
+				continue
+			}
+			path := filepath.Join(dir, name)
+			isDir := raw.Mask&syscall.IN_ISDIR != 0
+
+			switch {
+			case raw.Mask&syscall.IN_CREATE != 0:
+				events = append(events, Event{Op: EventCreate, Path: path})
+				if isDir {
+					if events, err = in.watchNewDir(path, events); err != nil {
+						return nil, err
+					}
+				}
+			case raw.Mask&syscall.IN_MODIFY != 0:
+				events = appendEvent(events, Event{Op: EventWrite, Path: path})
+			case raw.Mask&syscall.IN_DELETE != 0:
+				events = append(events, Event{Op: EventRemove, Path: path})
+			case raw.Mask&syscall.IN_MOVED_FROM != 0:
+				in.moves[raw.Cookie] = pendingMove{index: len(events), path: path}
+				events = append(events, Event{Op: EventRemove, Path: path})
+			case raw.Mask&syscall.IN_MOVED_TO != 0:
+				move, ok := in.moves[raw.Cookie]
+				delete(in.moves, raw.Cookie)
+				// the remove event is gone when the events were reset after the
+				// source of the move was read.
+				i := move.index
+				if !ok || i >= len(events) || events[i] != (Event{Op: EventRemove, Path: move.path}) {
+					events = append(events, Event{Op: EventCreate, Path: path})
+					if isDir {
+						if events, err = in.watchNewDir(path, events); err != nil {
+							return nil, err
+						}
+					}
+					continue
+				}
+				events[i] = Event{Op: EventRename, Path: events[i].Path, NewPath: path}
+				if isDir {
+					in.renameWatches(events[i].Path, path)
+				}
+			}
+		}
+	}
+}
+
+// watchNewDir adds a watch for a directory that was created after the watch
+// started, and for all of its subdirectories. The entries in the directories
+// may have been created before the watch was added, so a create event is added
+// for each entry.
+func (in *inotify) watchNewDir(rel string, events []Event) ([]Event, error) {
+	walk := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		entry, err := filepath.Rel(in.root, path)
+		if err != nil {
+			return err
+		}
+		if entry != rel {
+			events = append(events, Event{Op: EventCreate, Path: entry})
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		return in.addWatch(entry)
+	}
+	err := filepath.Walk(filepath.Join(in.root, rel), walk)
+	if os.IsNotExist(err) {
+		return events, nil
+	}
+	return events, err
+}
+
+// renameWatches updates the paths of the watched directories after the
+// directory at oldPath was renamed to newPath.
+func (in *inotify) renameWatches(oldPath, newPath string) {
+	for wd, path := range in.paths {
+		switch {
+		case path == oldPath:
+			in.paths[wd] = newPath
+		case strings.HasPrefix(path, oldPath+string(os.PathSeparator)):
+			in.paths[wd] = newPath + strings.TrimPrefix(path, oldPath)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// writeFileAtomic writes a file to a temporary file, and renames the temporary
+// file to the path.
+func writeFileAtomic(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	assert.NilError(t, os.WriteFile(tmp, []byte(content), 0644))
+	assert.NilError(t, os.Rename(tmp, path))
+}
+
+func TestWatch(t *testing.T) {
+	dir := NewDir(t, t.Name(),
+		WithFile("config.yaml", "old"),
+		WithDir("logs", WithFile("app.log", "")))
+	watcher := Watch(t, dir.Path())
+
+	writeFileAtomic(t, dir.Join("config.yaml"), "new")
+	assert.NilError(t, os.Rename(dir.Join("logs", "app.log"), dir.Join("logs", "app.log.1")))
+	assert.NilError(t, os.WriteFile(dir.Join("logs", "app.log"), []byte("one\n"), 0644))
+	assert.NilError(t, os.Remove(dir.Join("logs", "app.log.1")))
+	assert.NilError(t, os.Mkdir(dir.Join("data"), 0755))
+	assert.NilError(t, os.WriteFile(dir.Join("data", "file"), nil, 0644))
+
+	expected := []Event{
+		CreateEvent("config.yaml.tmp"),
+		WriteEvent("config.yaml.tmp"),
+		RenameEvent("config.yaml.tmp", "config.yaml"),
+		RenameEvent("logs/app.log", "logs/app.log.1"),
+		CreateEvent("logs/app.log"),
+		WriteEvent("logs/app.log"),
+		RemoveEvent("logs/app.log.1"),
+		CreateEvent("data"),
+		CreateEvent("data/file"),
+	}
+	assert.DeepEqual(t, watcher.Events(), expected)
+	assert.Assert(t, ExpectEvents(watcher, expected...))
+
+	watcher.Reset()
+	assert.Assert(t, is.Len(watcher.Events(), 0))
+}
+
+func TestWatch_RenameOutOfDirectory(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithDir("watched", WithFile("a", "")))
+	watcher := Watch(t, dir.Join("watched"))
+
+	assert.NilError(t, os.Rename(dir.Join("watched", "a"), dir.Join("a")))
+	assert.NilError(t, os.Rename(dir.Join("a"), dir.Join("watched", "b")))
+	assert.Assert(t, ExpectEvents(watcher, RemoveEvent("a"), CreateEvent("b")))
+}
+
+func TestInotifyRead_MoveSplitAcrossReads(t *testing.T) {
+	var fds [2]int
+	assert.NilError(t, syscall.Pipe2(fds[:], syscall.O_NONBLOCK|syscall.O_CLOEXEC))
+	in := &inotify{fd: fds[0], paths: map[int]string{1: ""}, moves: make(map[uint32]pendingMove)}
+	t.Cleanup(func() {
+		_ = syscall.Close(fds[0])
+		_ = syscall.Close(fds[1])
+	})
+
+	// writeEvent writes an event with a name of 16 bytes, padded with zeros,
+	// like the events returned by inotify.
+	writeEvent := func(mask uint32, cookie uint32, name string) {
+		raw := syscall.InotifyEvent{Wd: 1, Mask: mask, Cookie: cookie, Len: 16}
+		buf := make([]byte, syscall.SizeofInotifyEvent+16)
+		copy(buf, (*[syscall.SizeofInotifyEvent]byte)(unsafe.Pointer(&raw))[:])
+		copy(buf[syscall.SizeofInotifyEvent:], name)
+		_, err := syscall.Write(fds[1], buf)
+		assert.NilError(t, err)
+	}
+
+	writeEvent(syscall.IN_MOVED_FROM, 7, "old")
+	events, err := in.read(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, events, []Event{RemoveEvent("old")})
+
+	writeEvent(syscall.IN_MOVED_TO, 7, "new")
+	events, err = in.read(events)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, events, []Event{RenameEvent("old", "new")})
+
+	writeEvent(syscall.IN_MOVED_FROM, 8, "gone")
+	_, err = in.read(nil)
+	assert.NilError(t, err)
+	// the events were reset after the source of the move was read
+	writeEvent(syscall.IN_MOVED_TO, 8, "other")
+	events, err = in.read(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, events, []Event{CreateEvent("other")})
+}
+
+func TestExpectEvents_Failure(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("config", "old"))
+	watcher := Watch(t, dir.Path())
+	assert.NilError(t, os.WriteFile(dir.Join("config"), []byte("new"), 0644))
+	assert.NilError(t, os.Remove(dir.Join("config")))
+
+	result := ExpectEvents(watcher,
+		CreateEvent("config.tmp"),
+		RenameEvent("config.tmp", "config"))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(), fmtExpected(`directory %s does not have the expected events:
+/config.tmp
+  event 1: expected create config.tmp got write config
+  event 2: expected rename config.tmp -> config got remove config
+
+recorded events:
+  1. write config
+  2. remove config
+`, dir.Path()))
+}
+
+func TestExpectEventsUnordered(t *testing.T) {
+	dir := NewDir(t, t.Name())
+	watcher := Watch(t, dir.Path())
+	assert.NilError(t, os.WriteFile(dir.Join("a"), []byte("a"), 0644))
+	assert.NilError(t, os.WriteFile(dir.Join("b"), nil, 0644))
+
+	assert.Assert(t, ExpectEventsUnordered(watcher,
+		CreateEvent("b"),
+		WriteEvent("a"),
+		CreateEvent("a")))
+
+	result := ExpectEventsUnordered(watcher, CreateEvent("a"), RemoveEvent("b"))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, result.(cmpFailure).FailureMessage(), fmtExpected(`directory %s does not have the expected events:
+/a
+  unexpected event: write
+/b
+  expected remove, got no event
+  unexpected event: create
+
+recorded events:
+  1. create a
+  2. write a
+  3. create b
+`, dir.Path()))
+}
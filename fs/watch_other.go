@@ -0,0 +1,27 @@
+//go:build !linux
+// +build !linux
+
+package fs
+
+import (
+	"errors"
+)
+
+// inotify is not supported on this platform, newInotify always returns an
+// error.
+type inotify struct{}
+
+var errWatchUnsupported = errors.New("fs.Watch is only supported on linux")
+
+func newInotify(string) (*inotify, error) {
+	return nil, errWatchUnsupported
+}
+
+// read is never called, because newInotify always returns an error.
+func (in *inotify) read([]Event) ([]Event, error) {
+	return nil, errWatchUnsupported
+}
+
+func (in *inotify) close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package fs
+
+import (
+	"bytes"
+	"strings"
+	"syscall"
+)
+
+// readXattrs returns the extended attributes of the file at path in the user
+// and system namespaces. POSIX ACLs are stored in the system namespace. Other
+// namespaces, like security, are set by the system, and are not included.
+func readXattrs(path string) (map[string]string, error) {
+	names, err := listXattrs(path)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	xattrs := make(map[string]string, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, "user.") && !strings.HasPrefix(name, "system.") {
+			continue
+		}
+		value, err := getXattr(path, name)
+		if err != nil {
+			return nil, err
+		}
+		xattrs[name] = value
+	}
+	if len(xattrs) == 0 {
+		return nil, nil
+	}
+	return xattrs, nil
+}
+
+func listXattrs(path string) ([]string, error) {
+	size, err := syscall.Listxattr(path, nil)
+	switch {
+	case err == syscall.ENOTSUP:
+		return nil, nil
+	case err != nil || size == 0:
+		return nil, err
+	}
+	buf := make([]byte, size)
+	size, err = syscall.Listxattr(path, buf)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+	return names, nil
+}
+
+func getXattr(path string, name string) (string, error) {
+	size, err := syscall.Getxattr(path, name, nil)
+	if err != nil || size == 0 {
+		return "", err
+	}
+	buf := make([]byte, size)
+	size, err = syscall.Getxattr(path, name, buf)
+	return string(buf[:size]), err
+}
+
+func setXattr(path string, name string, value string) error {
+	return syscall.Setxattr(path, name, []byte(value), 0)
+}
@@ -0,0 +1,56 @@
+package fs
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithXattr(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("file", "content"))
+	err := WithXattr("user.comment", "hello")(&File{path: dir.Join("file")})
+	if errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EPERM) {
+		t.Skipf("extended attributes are not supported by the filesystem: %s", err)
+	}
+	assert.NilError(t, err)
+
+	manifest := ManifestFromDir(t, dir.Path())
+	assert.DeepEqual(t, manifest.root.items["file"].(*file).xattrs,
+		map[string]string{"user.comment": "hello"})
+
+	assert.Assert(t, Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithFile("file", "content", WithXattr("user.comment", "hello")))))
+
+	result := Equal(dir.Path(), Expected(t,
+		WithMode(0700),
+		WithXattr("user.dir", "x"),
+		WithFile("file", "content", WithXattr("user.comment", "bye")),
+	))()
+	assert.Assert(t, !result.Success())
+	assert.Equal(t, failureProblems(result), fmtExpected(`directory %s does not match expected:
+/
+  xattr user.dir: expected "x" got no value
+/file
+  xattr user.comment: expected "bye" got "hello"
+`, dir.Path()))
+}
+
+func TestManifestFromDir_SkipsXattrs(t *testing.T) {
+	dir := NewDir(t, t.Name(), WithFile("file", "content"))
+	err := WithXattr("user.comment", "hello")(&File{path: dir.Join("file")})
+	if errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EPERM) {
+		t.Skipf("extended attributes are not supported by the filesystem: %s", err)
+	}
+	assert.NilError(t, err)
+
+	manifest, err := manifestFromDir(dir.Path(), false)
+	assert.NilError(t, err)
+	assert.Assert(t, manifest.root.items["file"].(*file).xattrs == nil)
+
+	assert.Assert(t, !hasXattrs(Expected(t, WithFile("file", "content")).root))
+	assert.Assert(t, hasXattrs(Expected(t,
+		WithDir("sub", WithFile("file", "content", WithXattr("user.comment", "hello")))).root))
+}
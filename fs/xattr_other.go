@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package fs
+
+import (
+	"errors"
+)
+
+// readXattrs returns nil, extended attributes are only supported on linux.
+func readXattrs(string) (map[string]string, error) {
+	return nil, nil
+}
+
+func setXattr(string, string, string) error {
+	return errors.New("extended attributes are only supported on linux")
+}
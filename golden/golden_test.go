@@ -7,10 +7,18 @@ import (
 
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
-	"gotest.tools/v3/fs"
 	"gotest.tools/v3/internal/source"
 )
 
+// newFile creates a file with content in a temporary directory, and returns
+// its path. The fs package can not be used because it imports golden.
+func newFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "abs-test")
+	assert.NilError(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
 type fakeT struct {
 	Failed bool
 }
@@ -36,11 +44,10 @@ func TestGoldenOpenInvalidFile(t *testing.T) {
 }
 
 func TestGoldenOpenAbsolutePath(t *testing.T) {
-	file := fs.NewFile(t, "abs-test", fs.WithContent("content\n"))
-	defer file.Remove()
+	filename := newFile(t, "content\n")
 	fakeT := new(fakeT)
 
-	f := Open(fakeT, file.Path())
+	f := Open(fakeT, filename)
 	assert.Assert(t, !fakeT.Failed)
 	f.Close()
 }
@@ -64,11 +71,10 @@ func TestGoldenGetInvalidFile(t *testing.T) {
 }
 
 func TestGoldenGetAbsolutePath(t *testing.T) {
-	file := fs.NewFile(t, "abs-test", fs.WithContent("content\n"))
-	defer file.Remove()
+	filename := newFile(t, "content\n")
 	fakeT := new(fakeT)
 
-	Get(fakeT, file.Path())
+	Get(fakeT, filename)
 	assert.Assert(t, !fakeT.Failed)
 }
 
@@ -107,11 +113,10 @@ func TestGoldenAssertInvalidContentUpdate(t *testing.T) {
 }
 
 func TestGoldenAssertAbsolutePath(t *testing.T) {
-	file := fs.NewFile(t, "abs-test", fs.WithContent("foo"))
-	defer file.Remove()
+	filename := newFile(t, "foo")
 	fakeT := new(fakeT)
 
-	Assert(fakeT, "foo", file.Path())
+	Assert(fakeT, "foo", filename)
 	assert.Assert(t, !fakeT.Failed)
 }
 
@@ -268,10 +273,10 @@ func TestFlagUpdate(t *testing.T) {
 func TestUpdate_CreatesPathsAndFile(t *testing.T) {
 	setUpdateFlag(t)
 
-	dir := fs.NewDir(t, t.Name())
+	dir := t.TempDir()
 
 	t.Run("creates the file", func(t *testing.T) {
-		filename := dir.Join("filename")
+		filename := filepath.Join(dir, "filename")
 		err := update(filename, nil)
 		assert.NilError(t, err)
 
@@ -280,7 +285,7 @@ func TestUpdate_CreatesPathsAndFile(t *testing.T) {
 	})
 
 	t.Run("creates directories", func(t *testing.T) {
-		filename := dir.Join("one/two/filename")
+		filename := filepath.Join(dir, "one/two/filename")
 		err := update(filename, nil)
 		assert.NilError(t, err)
 
@@ -0,0 +1,229 @@
+/*
+Package httpassert provides comparisons for HTTP responses, for use with
+[gotest.tools/v3/assert.Assert] and [gotest.tools/v3/assert.Check].
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	resp := httpassert.Serve(handler, req)
+
+	assert.Assert(t, httpassert.StatusCode(resp, http.StatusOK))
+	assert.Check(t, httpassert.Header(resp, "Content-Type", "application/json"))
+	assert.Check(t, httpassert.JSONBody(resp, `{"name": "example"}`, opt.DocumentPath("$.id")))
+
+When a comparison fails the failure message includes the request and the
+response, like the message from [gotest.tools/v3/icmd.Result.Assert].
+*/
+package httpassert // import "gotest.tools/v3/httpassert"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+// Serve calls handler with req, and returns the response recorded by an
+// [httptest.ResponseRecorder]. The body of req is read before it is passed to
+// handler, so that it can be included in the failure messages of the
+// comparisons in this package.
+func Serve(handler http.Handler, req *http.Request) *http.Response {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err == nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return Recorded(rec, req)
+}
+
+// Recorded returns the response recorded by rec. req is the request that was
+// passed to the handler, and is included in the failure messages of the
+// comparisons in this package. The body of req is only included when
+// req.GetBody is set, because the handler has already read the body. Use
+// [Serve] to include the body of a request created by [httptest.NewRequest].
+func Recorded(rec *httptest.ResponseRecorder, req *http.Request) *http.Response {
+	resp := rec.Result()
+	resp.Request = req
+	return resp
+}
+
+// StatusCode succeeds if the status code of resp is equal to expected.
+func StatusCode(resp *http.Response, expected int) cmp.Comparison {
+	return func() cmp.Result {
+		if resp.StatusCode == expected {
+			return cmp.ResultSuccess
+		}
+		return failure(resp, fmt.Sprintf("StatusCode was %d expected %d",
+			resp.StatusCode, expected))
+	}
+}
+
+// HasHeader succeeds if resp has a header with the name, with any value.
+func HasHeader(resp *http.Response, name string) cmp.Comparison {
+	return func() cmp.Result {
+		if _, ok := resp.Header[http.CanonicalHeaderKey(name)]; ok {
+			return cmp.ResultSuccess
+		}
+		return failure(resp, fmt.Sprintf("Expected header %q to be set", name))
+	}
+}
+
+// Header succeeds if the first value of the header with the name is equal to
+// expected.
+func Header(resp *http.Response, name string, expected string) cmp.Comparison {
+	return func() cmp.Result {
+		values, ok := resp.Header[http.CanonicalHeaderKey(name)]
+		switch {
+		case !ok || len(values) == 0:
+			return failure(resp, fmt.Sprintf("Expected header %q to be %q, but it was not set",
+				name, expected))
+		case values[0] != expected:
+			return failure(resp, fmt.Sprintf("Header %q was %q expected %q",
+				name, values[0], expected))
+		}
+		return cmp.ResultSuccess
+	}
+}
+
+// JSONBody succeeds if the body of resp and expected are equivalent JSON
+// documents. The documents are compared with [cmp.JSONEqual], values at paths
+// which match any of the filters are ignored. See
+// [gotest.tools/v3/assert/opt.DocumentPath] for a filter which matches paths
+// by pattern.
+//
+// The failure message lists the path of each value that is different, with
+// the value from the response before the expected value.
+func JSONBody(resp *http.Response, expected string, ignore ...cmp.DocumentPathFilter) cmp.Comparison {
+	return func() cmp.Result {
+		body, err := readBody(resp)
+		if err != nil {
+			return failure(resp, err.Error())
+		}
+		result := cmp.JSONEqual(body, expected, ignore...)()
+		if result.Success() {
+			return result
+		}
+		msg := "Body does not match the expected JSON"
+		if stringResult, ok := result.(cmp.StringResult); ok {
+			msg += ": " + stringResult.FailureMessage()
+		}
+		return failure(resp, msg)
+	}
+}
+
+// GoldenBody succeeds if the body of resp is equal to the contents of the golden
+// file. See [golden.String] for details about the golden file, and how to
+// update it.
+func GoldenBody(resp *http.Response, filename string) cmp.Comparison {
+	return func() cmp.Result {
+		body, err := readBody(resp)
+		if err != nil {
+			return failure(resp, err.Error())
+		}
+		result := golden.String(string(body), filename)()
+		if result.Success() {
+			return result
+		}
+		msg := "Body does not match the golden file"
+		if stringResult, ok := result.(cmp.StringResult); ok {
+			msg += ": " + stringResult.FailureMessage()
+		}
+		return failure(resp, msg)
+	}
+}
+
+// readBody reads the body of resp, and replaces resp.Body so that the body can
+// be read again by other comparisons.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+func failure(resp *http.Response, msg string) cmp.Result {
+	return cmp.ResultFailure(fmt.Sprintf("%s\nFailures:\n%s", formatExchange(resp), msg))
+}
+
+// formatExchange returns the request and response as text, similar to the
+// HTTP/1.1 wire format.
+func formatExchange(resp *http.Response) string {
+	buf := new(strings.Builder)
+	if req := resp.Request; req != nil {
+		buf.WriteString("\nRequest:\n")
+		fmt.Fprintf(buf, "%s %s %s\n", req.Method, req.URL.RequestURI(), protoOrDefault(req.Proto))
+		if req.Host != "" {
+			fmt.Fprintf(buf, "Host: %s\n", req.Host)
+		}
+		formatHeader(buf, req.Header)
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				content, _ := io.ReadAll(body)
+				_ = body.Close()
+				formatBody(buf, content)
+			}
+		}
+	}
+
+	buf.WriteString("\nResponse:\n")
+	fmt.Fprintf(buf, "%s %s\n", protoOrDefault(resp.Proto), statusOrDefault(resp))
+	formatHeader(buf, resp.Header)
+	if body, err := readBody(resp); err == nil {
+		formatBody(buf, body)
+	}
+	return buf.String()
+}
+
+func protoOrDefault(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func statusOrDefault(resp *http.Response) string {
+	if resp.Status != "" {
+		return resp.Status
+	}
+	return fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+}
+
+func formatHeader(buf io.Writer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func formatBody(buf io.Writer, content []byte) {
+	if len(content) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\n%s", content)
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		fmt.Fprintln(buf)
+	}
+}
@@ -0,0 +1,150 @@
+package httpassert
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/assert/opt"
+)
+
+func newResponse(t *testing.T, status int, contentType string, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/users/1?verbose=true", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", contentType)
+	rec.WriteHeader(status)
+	_, _ = io.WriteString(rec, body)
+	return Recorded(rec, req)
+}
+
+func failureMessage(t *testing.T, comparison cmp.Comparison) string {
+	t.Helper()
+	result := comparison()
+	assert.Assert(t, !result.Success(), "expected the comparison to fail")
+	return result.(cmp.StringResult).FailureMessage()
+}
+
+func TestStatusCode(t *testing.T) {
+	resp := newResponse(t, http.StatusNotFound, "text/plain", "user not found")
+	assert.Check(t, StatusCode(resp, http.StatusNotFound))
+
+	expected := `
+Request:
+GET /users/1?verbose=true HTTP/1.1
+Host: example.com
+Accept: application/json
+
+Response:
+HTTP/1.1 404 Not Found
+Content-Type: text/plain
+
+user not found
+
+Failures:
+StatusCode was 404 expected 200`
+	assert.Equal(t, failureMessage(t, StatusCode(resp, http.StatusOK)), expected)
+}
+
+func TestHeader(t *testing.T) {
+	resp := newResponse(t, http.StatusOK, "application/json", "{}")
+	assert.Check(t, HasHeader(resp, "content-type"))
+	assert.Check(t, Header(resp, "Content-Type", "application/json"))
+
+	msg := failureMessage(t, HasHeader(resp, "ETag"))
+	assert.Check(t, cmp.Contains(msg, "Failures:\nExpected header \"ETag\" to be set"))
+
+	msg = failureMessage(t, Header(resp, "Content-Type", "text/plain"))
+	assert.Check(t, cmp.Contains(msg,
+		`Failures:
+Header "Content-Type" was "application/json" expected "text/plain"`))
+
+	msg = failureMessage(t, Header(resp, "ETag", "abc"))
+	assert.Check(t, cmp.Contains(msg,
+		`Failures:
+Expected header "ETag" to be "abc", but it was not set`))
+
+	resp.Header["X-Empty"] = []string{}
+	msg = failureMessage(t, Header(resp, "X-Empty", "abc"))
+	assert.Check(t, cmp.Contains(msg,
+		`Failures:
+Expected header "X-Empty" to be "abc", but it was not set`))
+}
+
+func TestJSONBody(t *testing.T) {
+	resp := newResponse(t, http.StatusOK, "application/json",
+		`{"id": 9007199254740993, "name": "example", "tags": [{"id": 1, "name": "a"}]}`)
+
+	assert.Check(t, JSONBody(resp, `{"name": "example", "tags": [{"name": "a"}]}`,
+		opt.DocumentPath("$.id", "$.tags[*].id")))
+	// the body can be read more than once
+	assert.Check(t, JSONBody(resp,
+		`{"id": 9007199254740993, "name": "example", "tags": [{"name": "a", "id": 1}]}`))
+
+	msg := failureMessage(t, JSONBody(resp, `{"id": 9007199254740992, "name": "other", "tags": [{"name": "a"}]}`,
+		opt.DocumentPath("$.tags[0].id")))
+	assert.Check(t, cmp.Contains(msg, `{"id": 9007199254740993, "name": "example"`), "response body is missing")
+	assert.Check(t, cmp.Contains(msg, `Failures:
+Body does not match the expected JSON: documents are not equal:
+$.id: 9007199254740993 != 9007199254740992
+$.name: "example" != "other"`))
+
+	msg = failureMessage(t, JSONBody(resp, `{"name":`))
+	assert.Check(t, cmp.Contains(msg,
+		"Failures:\nBody does not match the expected JSON: failed to decode expected document (y): "))
+}
+
+func TestGoldenBody(t *testing.T) {
+	resp := newResponse(t, http.StatusOK, "text/plain", "hello world\n")
+	assert.Check(t, GoldenBody(resp, "hello.golden"))
+
+	resp = newResponse(t, http.StatusOK, "text/plain", "hello gopher\n")
+	msg := failureMessage(t, GoldenBody(resp, "hello.golden"))
+	assert.Check(t, cmp.Contains(msg, "Failures:\nBody does not match the golden file: \n"))
+	assert.Check(t, cmp.Contains(msg, "+hello gopher"))
+}
+
+func TestFormatExchange_WithRequestBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/users", strings.NewReader(`{"name": "example"}`))
+	assert.NilError(t, err)
+	resp := &http.Response{StatusCode: http.StatusCreated, Request: req}
+
+	expected := `
+Request:
+POST /users HTTP/1.1
+Host: example.com
+
+{"name": "example"}
+
+Response:
+HTTP/1.1 201 Created
+`
+	assert.Equal(t, formatExchange(resp), expected)
+}
+
+func TestServe_IncludesRequestBody(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name": "example"}`))
+	resp := Serve(handler, req)
+
+	msg := failureMessage(t, StatusCode(resp, http.StatusCreated))
+	expected := `
+Request:
+POST /users HTTP/1.1
+Host: example.com
+
+{"name": "example"}
+
+Response:
+HTTP/1.1 400 Bad Request
+`
+	assert.Check(t, cmp.Contains(msg, expected))
+}
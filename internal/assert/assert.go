@@ -4,8 +4,10 @@ package assert
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"reflect"
+	"strings"
 
 	"gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/internal/format"
@@ -33,33 +35,41 @@ func Eval(
 	if ht, ok := t.(helperT); ok {
 		ht.Helper()
 	}
+	const stackIndex = 3 // Assert/Check, assert, runComparison
+	failure := &Failure{}
 	var success bool
 	switch check := comparison.(type) {
 	case bool:
 		if check {
 			return true
 		}
-		logFailureFromBool(t, msgAndArgs...)
+		failure.Message = logFailureFromBool(t, msgAndArgs...)
 
 	// Undocumented legacy comparison without Result type
 	case func() (success bool, message string):
-		success = runCompareFunc(t, check, msgAndArgs...)
+		success, failure.Message = runCompareFunc(t, check, msgAndArgs...)
 
 	case nil:
 		return true
 
 	case error:
 		msg := failureMsgFromError(check)
-		t.Log(format.WithCustomMessage(failureMessage+msg, msgAndArgs...))
+		failure.Message = format.WithCustomMessage(failureMessage+msg, msgAndArgs...)
+		t.Log(failure.Message)
 
 	case cmp.Comparison:
-		success = RunComparison(t, argSelector, check, msgAndArgs...)
+		success = runComparison(t, stackIndex, argSelector, check, failure, msgAndArgs...)
 
 	case func() cmp.Result:
-		success = RunComparison(t, argSelector, check, msgAndArgs...)
+		success = runComparison(t, stackIndex, argSelector, check, failure, msgAndArgs...)
 
 	default:
-		t.Log(fmt.Sprintf("invalid Comparison: %v (%T)", check, check))
+		failure.Message = fmt.Sprintf("invalid Comparison: %v (%T)", check, check)
+		t.Log(failure.Message)
+	}
+	if !success {
+		const assertionIndex = 1 // Assert/Check
+		report(assertionIndex, failure)
 	}
 	return success
 }
@@ -68,18 +78,19 @@ func runCompareFunc(
 	t LogT,
 	f func() (success bool, message string),
 	msgAndArgs ...interface{},
-) bool {
+) (bool, string) {
 	if ht, ok := t.(helperT); ok {
 		ht.Helper()
 	}
 	if success, message := f(); !success {
-		t.Log(format.WithCustomMessage(failureMessage+message, msgAndArgs...))
-		return false
+		message = format.WithCustomMessage(failureMessage+message, msgAndArgs...)
+		t.Log(message)
+		return false, message
 	}
-	return true
+	return true, ""
 }
 
-func logFailureFromBool(t LogT, msgAndArgs ...interface{}) {
+func logFailureFromBool(t LogT, msgAndArgs ...interface{}) string {
 	if ht, ok := t.(helperT); ok {
 		ht.Helper()
 	}
@@ -101,7 +112,9 @@ func logFailureFromBool(t LogT, msgAndArgs ...interface{}) {
 		}
 	}
 
-	t.Log(format.WithCustomMessage(failureMessage+msg, msgAndArgs...))
+	msg = format.WithCustomMessage(failureMessage+msg, msgAndArgs...)
+	t.Log(msg)
+	return msg
 }
 
 func failureMsgFromError(err error) string {
@@ -114,47 +127,184 @@ func failureMsgFromError(err error) string {
 }
 
 func boolFailureMessage(expr ast.Expr) (string, error) {
+	msg, ok, err := falseExprMessage(expr)
+	if err != nil || ok {
+		return msg, err
+	}
+
+	formatted, err := source.FormatNode(expr)
+	if err != nil {
+		return "", err
+	}
+	return "expression is false: " + formatted, nil
+}
+
+// falseExprMessage returns a message which describes why expr is false. If
+// there is no better description than the source of expr, ok is false.
+func falseExprMessage(expr ast.Expr) (msg string, ok bool, err error) {
+	expr = unparen(expr)
 	if binaryExpr, ok := expr.(*ast.BinaryExpr); ok {
-		x, err := source.FormatNode(binaryExpr.X)
+		if binaryExpr.Op == token.LAND || binaryExpr.Op == token.LOR {
+			return compoundFailureMessage(binaryExpr)
+		}
+
+		x, err := formatOperand(binaryExpr.X)
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
-		y, err := source.FormatNode(binaryExpr.Y)
+		y, err := formatOperand(binaryExpr.Y)
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 
 		switch binaryExpr.Op {
 		case token.NEQ:
-			return x + " is " + y, nil
+			return x + " is " + y, true, nil
 		case token.EQL:
-			return x + " is not " + y, nil
+			return x + " is not " + y, true, nil
 		case token.GTR:
-			return x + " is <= " + y, nil
+			return x + " is <= " + y, true, nil
 		case token.LSS:
-			return x + " is >= " + y, nil
+			return x + " is >= " + y, true, nil
 		case token.GEQ:
-			return x + " is less than " + y, nil
+			return x + " is less than " + y, true, nil
 		case token.LEQ:
-			return x + " is greater than " + y, nil
+			return x + " is greater than " + y, true, nil
 		}
 	}
 
 	if unaryExpr, ok := expr.(*ast.UnaryExpr); ok && unaryExpr.Op == token.NOT {
-		x, err := source.FormatNode(unaryExpr.X)
+		x, err := source.FormatNode(unparen(unaryExpr.X))
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
-		return x + " is true", nil
+		return x + " is true", true, nil
 	}
 
 	if ident, ok := expr.(*ast.Ident); ok {
-		return ident.Name + " is false", nil
+		return ident.Name + " is false", true, nil
+	}
+	return "", false, nil
+}
+
+// compoundFailureMessage returns a message for a false && or || expression.
+// When a chain of || is false, every operand is false, and each one is
+// described. When a chain of && is false, only the first false operand was
+// checked, and the operands after it were not evaluated.
+//
+// The failure message is built from the source of the assertion, so the values
+// of variables, fields, and function calls are not available. Only constant
+// expressions can be evaluated, see constantValue. The false operand of && is
+// known when every operand before it is a constant true expression, and it is
+// either a constant false expression, or every operand after it is a constant
+// true expression. Otherwise ok is false, and the message does not guess which
+// operand was false.
+func compoundFailureMessage(expr *ast.BinaryExpr) (msg string, ok bool, err error) {
+	operands := flattenOperands(expr, expr.Op)
+	if expr.Op == token.LOR {
+		msgs := make([]string, 0, len(operands))
+		for _, operand := range operands {
+			msg, err := operandFailureMessage(operand)
+			if err != nil {
+				return "", false, err
+			}
+			msgs = append(msgs, msg)
+		}
+		return strings.Join(msgs, ", and "), true, nil
 	}
 
+	for i, operand := range operands {
+		value, known := constantBool(operand)
+		switch {
+		case known && value:
+			continue
+		case !known && !allConstantTrue(operands[i+1:]):
+			return "", false, nil
+		}
+		msg, err := operandFailureMessage(operand)
+		return msg, err == nil, err
+	}
+	return "", false, nil
+}
+
+// allConstantTrue returns true if every expr is a constant true expression.
+func allConstantTrue(exprs []ast.Expr) bool {
+	for _, expr := range exprs {
+		if value, known := constantBool(expr); !known || !value {
+			return false
+		}
+	}
+	return true
+}
+
+// operandFailureMessage returns a message for an operand of a false && or ||
+// expression that is known to be false.
+func operandFailureMessage(operand ast.Expr) (string, error) {
+	msg, ok, err := falseExprMessage(operand)
+	switch {
+	case err != nil:
+		return "", err
+	case !ok:
+		formatted, err := source.FormatNode(unparen(operand))
+		if isCompound(unparen(operand)) {
+			formatted = "(" + formatted + ")"
+		}
+		return formatted + " is false", err
+	case isCompound(unparen(operand)):
+		return "(" + msg + ")", nil
+	}
+	return msg, nil
+}
+
+// flattenOperands returns the operands of a chain of op, ex: a && b && c
+// returns a, b, and c.
+func flattenOperands(expr ast.Expr, op token.Token) []ast.Expr {
+	binaryExpr, ok := unparen(expr).(*ast.BinaryExpr)
+	if !ok || binaryExpr.Op != op {
+		return []ast.Expr{expr}
+	}
+	return append(flattenOperands(binaryExpr.X, op), flattenOperands(binaryExpr.Y, op)...)
+}
+
+func isCompound(expr ast.Expr) bool {
+	binaryExpr, ok := expr.(*ast.BinaryExpr)
+	return ok && (binaryExpr.Op == token.LAND || binaryExpr.Op == token.LOR)
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		parenExpr, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = parenExpr.X
+	}
+}
+
+// constantBool returns the value of expr if it is a constant boolean
+// expression.
+func constantBool(expr ast.Expr) (value bool, ok bool) {
+	v, ok := constantValue(expr)
+	if !ok || v.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(v), true
+}
+
+// formatOperand returns the source of an operand of a comparison. When the
+// operand is a constant expression, and not a literal, the value is added
+// after the source, ex: limit (3).
+func formatOperand(expr ast.Expr) (string, error) {
 	formatted, err := source.FormatNode(expr)
 	if err != nil {
 		return "", err
 	}
-	return "expression is false: " + formatted, nil
+	if _, ok := unparen(expr).(*ast.BasicLit); ok {
+		return formatted, nil
+	}
+	value, ok := constantValue(expr)
+	if !ok || value.String() == formatted {
+		return formatted, nil
+	}
+	return fmt.Sprintf("%s (%s)", formatted, value), nil
 }
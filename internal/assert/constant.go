@@ -0,0 +1,122 @@
+package assert
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"gotest.tools/v3/internal/source"
+)
+
+// maxConstantDepth limits how many constant declarations are followed to
+// evaluate an expression.
+const maxConstantDepth = 10
+
+// constantValue returns the value of expr if it is a constant expression.
+// Identifiers of constants declared in the same file as expr, either at the
+// package level or in a function, are replaced by the value from their
+// declaration. ok is false if expr references variables, functions, fields,
+// or constants declared in other files or packages, because their values are
+// not available to the failure message.
+func constantValue(expr ast.Expr) (value constant.Value, ok bool) {
+	pkg := types.NewPackage("constants", "constants")
+	tv, ok := evalConstant(pkg, expr, 0)
+	if !ok {
+		return nil, false
+	}
+	return tv.Value, true
+}
+
+func evalConstant(pkg *types.Package, expr ast.Expr, depth int) (types.TypeAndValue, bool) {
+	if depth > maxConstantDepth || !declareConstants(pkg, expr, depth) {
+		return types.TypeAndValue{}, false
+	}
+	formatted, err := source.FormatNode(expr)
+	if err != nil {
+		return types.TypeAndValue{}, false
+	}
+	tv, err := types.Eval(token.NewFileSet(), pkg, token.NoPos, formatted)
+	if err != nil || tv.Value == nil {
+		return types.TypeAndValue{}, false
+	}
+	return tv, true
+}
+
+// declareConstants adds the constants referenced by expr to the scope of pkg.
+// It returns false if expr references an identifier that is declared in the
+// file, but is not a constant.
+func declareConstants(pkg *types.Package, expr ast.Expr, depth int) bool {
+	ok := true
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch typed := node.(type) {
+		case *ast.SelectorExpr:
+			// the selected name is resolved by the type of X
+			ast.Inspect(typed.X, func(node ast.Node) bool {
+				ident, isIdent := node.(*ast.Ident)
+				if isIdent && !declareConstant(pkg, ident, depth) {
+					ok = false
+				}
+				return ok
+			})
+			return false
+		case *ast.Ident:
+			if !declareConstant(pkg, typed, depth) {
+				ok = false
+			}
+		}
+		return ok
+	})
+	return ok
+}
+
+// declareConstant adds the constant ident to the scope of pkg. Identifiers
+// that are not declared in the file, like the predeclared identifiers, are
+// left for types.Eval to resolve.
+func declareConstant(pkg *types.Package, ident *ast.Ident, depth int) bool {
+	obj := ident.Obj
+	switch {
+	case obj == nil:
+		return true
+	case obj.Kind != ast.Con:
+		return false
+	}
+	if existing := pkg.Scope().Lookup(ident.Name); existing != nil {
+		return existing.Pos() == token.Pos(ident.Obj.Pos())
+	}
+
+	spec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return false
+	}
+	index := -1
+	for i, name := range spec.Names {
+		if name.Name == ident.Name {
+			index = i
+		}
+	}
+	// constants with an implicit value, like iota, are not supported
+	if index < 0 || index >= len(spec.Values) {
+		return false
+	}
+
+	tv, ok := evalConstant(pkg, spec.Values[index], depth+1)
+	if !ok {
+		return false
+	}
+	typ := tv.Type
+	if spec.Type != nil {
+		// only predeclared types are supported, ex: const limit int = 3
+		formatted, err := source.FormatNode(spec.Type)
+		if err != nil {
+			return false
+		}
+		typeTV, err := types.Eval(token.NewFileSet(), nil, token.NoPos, formatted)
+		if err != nil || !typeTV.IsType() {
+			return false
+		}
+		typ = typeTV.Type
+	}
+	pkg.Scope().Insert(types.NewConst(token.Pos(obj.Pos()), pkg, ident.Name, typ, tv.Value))
+	return true
+}
@@ -0,0 +1,94 @@
+package assert
+
+import (
+	"path"
+	"runtime"
+	"sync"
+
+	"gotest.tools/v3/internal/source"
+)
+
+// Failure is a structured description of a failed assertion. The fields must
+// match gotest.tools/v3/assert.Failure so that the types can be converted.
+type Failure struct {
+	Assertion  string
+	Expression string
+	Args       []string
+	File       string
+	Line       int
+	Actual     interface{}
+	Expected   interface{}
+	Diff       string
+	Message    string
+}
+
+var reporters = struct {
+	sync.Mutex
+	next  int
+	funcs map[int]func(Failure)
+}{funcs: make(map[int]func(Failure))}
+
+// RegisterReporter adds a function that is called with every failed assertion.
+// The returned function removes the reporter.
+func RegisterReporter(report func(Failure)) (unregister func()) {
+	reporters.Lock()
+	defer reporters.Unlock()
+	id := reporters.next
+	reporters.next++
+	reporters.funcs[id] = report
+	return func() {
+		reporters.Lock()
+		defer reporters.Unlock()
+		delete(reporters.funcs, id)
+	}
+}
+
+func registeredReporters() []func(Failure) {
+	reporters.Lock()
+	defer reporters.Unlock()
+	result := make([]func(Failure), 0, len(reporters.funcs))
+	for id := 0; id < reporters.next; id++ {
+		if report, ok := reporters.funcs[id]; ok {
+			result = append(result, report)
+		}
+	}
+	return result
+}
+
+// report sends the failure to all the registered reporters. stackIndex is the
+// index of the assertion function (ex: assert.Equal) in the call stack of the
+// caller of report.
+func report(stackIndex int, failure *Failure) {
+	funcs := registeredReporters()
+	if len(funcs) == 0 {
+		return
+	}
+
+	if pc, _, _, ok := runtime.Caller(stackIndex + 1); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			// trim the package path, ex: gotest.tools/v3/assert.Equal -> assert.Equal
+			failure.Assertion = path.Base(fn.Name())
+		}
+	}
+	_, failure.File, failure.Line, _ = runtime.Caller(stackIndex + 2)
+
+	if expr, err := source.CallExpr(stackIndex + 2); err == nil {
+		failure.Expression, _ = source.FormatNode(expr)
+		for _, arg := range ArgsAfterT(expr.Args) {
+			formatted, _ := source.FormatNode(arg)
+			failure.Args = append(failure.Args, formatted)
+		}
+	}
+
+	for _, report := range funcs {
+		report(*failure)
+	}
+}
+
+type resultWithComparedValues interface {
+	ComparedValues() (actual, expected interface{}, ok bool)
+}
+
+type resultWithDiff interface {
+	Diff() string
+}
@@ -21,6 +21,27 @@ func RunComparison(
 	if ht, ok := t.(helperT); ok {
 		ht.Helper()
 	}
+	const stackIndex = 4 // Assert/Check, assert, RunComparison, runComparison
+	return runComparison(t, stackIndex, argSelector, f, nil, msgAndArgs...)
+}
+
+// runComparison is RunComparison with a failure that is populated with the
+// details of the failed comparison. failure may be nil. stackIndex is the index
+// of the call to the assertion in the call stack.
+func runComparison(
+	t LogT,
+	stackIndex int,
+	argSelector argSelector,
+	f cmp.Comparison,
+	failure *Failure,
+	msgAndArgs ...interface{},
+) bool {
+	if ht, ok := t.(helperT); ok {
+		ht.Helper()
+	}
+	if failure == nil {
+		failure = &Failure{}
+	}
 	result := f()
 	if result.Success() {
 		return true
@@ -28,7 +49,6 @@ func RunComparison(
 
 	if source.IsUpdate() {
 		if updater, ok := result.(updateExpected); ok {
-			const stackIndex = 3 // Assert/Check, assert, RunComparison
 			err := updater.UpdatedExpected(stackIndex)
 			switch {
 			case err == nil:
@@ -36,6 +56,7 @@ func RunComparison(
 			case errors.Is(err, source.ErrNotFound):
 				// do nothing, fallthrough to regular failure message
 			default:
+				failure.Message = fmt.Sprint("failed to update source ", err)
 				t.Log("failed to update source", err)
 				return false
 			}
@@ -45,7 +66,6 @@ func RunComparison(
 	var message string
 	switch typed := result.(type) {
 	case resultWithComparisonArgs:
-		const stackIndex = 3 // Assert/Check, assert, RunComparison
 		args, err := source.CallExprArgs(stackIndex)
 		if err != nil {
 			t.Log(err.Error())
@@ -57,10 +77,29 @@ func RunComparison(
 		message = fmt.Sprintf("comparison returned invalid Result type: %T", result)
 	}
 
-	t.Log(format.WithCustomMessage(failureMessage+message, msgAndArgs...))
+	if typed, ok := result.(resultWithComparedValues); ok {
+		failure.Actual, failure.Expected, _ = typed.ComparedValues()
+	}
+	if typed, ok := result.(resultWithDiff); ok {
+		failure.Diff = typed.Diff()
+	}
+	failure.Message = format.WithCustomMessage(failureMessage+message, msgAndArgs...)
+	t.Log(failure.Message)
 	return false
 }
 
+// FailureMessage returns the failure message of a failed result, without the
+// source of the comparison args.
+func FailureMessage(result cmp.Result) string {
+	switch typed := result.(type) {
+	case resultWithComparisonArgs:
+		return typed.FailureMessage(nil)
+	case resultBasic:
+		return typed.FailureMessage()
+	}
+	return fmt.Sprintf("comparison returned invalid Result type: %T", result)
+}
+
 type resultWithComparisonArgs interface {
 	FailureMessage(args []ast.Expr) string
 }
@@ -20,6 +20,9 @@ type DiffConfig struct {
 	B    string
 	From string
 	To   string
+	// Highlight adds a line after each changed line which marks the range of
+	// characters that are different from the line it replaced.
+	Highlight bool
 }
 
 // UnifiedDiff is a modified version of difflib.WriteUnifiedDiff with better
@@ -39,7 +42,9 @@ func UnifiedDiff(conf DiffConfig) string {
 	writeLine := func(prefix string, s string) {
 		buf.WriteString(prefix + s)
 	}
-	if hasWhitespaceDiffLines(groups, a, b) {
+	writeMarker := writeLine
+	visibleWhitespace := hasWhitespaceDiffLines(groups, a, b)
+	if visibleWhitespace {
 		writeLine = visibleWhitespaceLine(writeLine)
 	}
 	formatHeader(writeFormat, conf)
@@ -52,6 +57,10 @@ func UnifiedDiff(conf DiffConfig) string {
 				formatLines(writeLine, " ", in)
 			case 'r':
 				formatLines(writeLine, "-", in)
+				if conf.Highlight && len(in) == len(out) {
+					formatHighlightedLines(writeLine, writeMarker, in, out, !visibleWhitespace)
+					continue
+				}
 				formatLines(writeLine, "+", out)
 			case 'd':
 				formatLines(writeLine, "-", in)
@@ -160,3 +169,55 @@ func formatLines(writeLine func(string, string), prefix string, lines []string)
 		writeLine("", "\n")
 	}
 }
+
+// formatHighlightedLines writes each line in out followed by a line that
+// marks the characters which are different from the matching line in in.
+func formatHighlightedLines(
+	writeLine, writeMarker func(string, string),
+	in, out []string,
+	keepTabs bool,
+) {
+	for i := range out {
+		formatLines(writeLine, "+", out[i:i+1])
+		if marker := highlightChange(in[i], out[i], keepTabs); marker != "" {
+			writeMarker("?", marker)
+		}
+	}
+}
+
+// highlightChange returns a line with ^ below the characters in b that are
+// different from a. An empty string is returned if the lines have nothing in
+// common, because highlighting the entire line would not help the reader.
+//
+// If keepTabs is true, tabs before the change are copied to the marker line so
+// that the marker lines up with the characters above it.
+func highlightChange(a, b string, keepTabs bool) string {
+	ra := []rune(strings.TrimSuffix(a, "\n"))
+	rb := []rune(strings.TrimSuffix(b, "\n"))
+
+	prefix := 0
+	for prefix < len(ra) && prefix < len(rb) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(ra)-prefix && suffix < len(rb)-prefix &&
+		ra[len(ra)-1-suffix] == rb[len(rb)-1-suffix] {
+		suffix++
+	}
+	if prefix == 0 && suffix == 0 {
+		return ""
+	}
+
+	width := len(rb) - prefix - suffix
+	if width < 1 {
+		width = 1
+	}
+	indent := make([]rune, prefix)
+	for i := range indent {
+		indent[i] = ' '
+		if keepTabs && rb[i] == '\t' {
+			indent[i] = '\t'
+		}
+	}
+	return string(indent) + strings.Repeat("^", width) + "\n"
+}
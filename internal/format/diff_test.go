@@ -10,12 +10,13 @@ import (
 
 func TestUnifiedDiff(t *testing.T) {
 	var testcases = []struct {
-		name     string
-		a        string
-		b        string
-		expected string
-		from     string
-		to       string
+		name      string
+		a         string
+		b         string
+		expected  string
+		from      string
+		to        string
+		highlight bool
 	}{
 		{
 			name: "empty diff",
@@ -50,15 +51,23 @@ func TestUnifiedDiff(t *testing.T) {
 			b:        "  something\n\tsomething\n  \n",
 			expected: "whitespace-diff.golden",
 		},
+		{
+			name:      "highlight changes",
+			a:         "a123\n\tindented value\nc\nreplaced\nz\n",
+			b:         "a123\n\tindented valve\nc\nnot similar\nz\n",
+			expected:  "highlight-diff.golden",
+			highlight: true,
+		},
 	}
 
 	for _, testcase := range testcases {
 		t.Run(testcase.name, func(t *testing.T) {
 			diff := format.UnifiedDiff(format.DiffConfig{
-				A:    testcase.a,
-				B:    testcase.b,
-				From: testcase.from,
-				To:   testcase.to,
+				A:         testcase.a,
+				B:         testcase.b,
+				From:      testcase.from,
+				To:        testcase.to,
+				Highlight: testcase.highlight,
 			})
 
 			if testcase.expected != "" {
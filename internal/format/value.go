@@ -0,0 +1,283 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValueConfig for formatting a value
+type ValueConfig struct {
+	// MaxDepth is the number of nested values that are printed. Values that
+	// are nested deeper are replaced with {...}. Zero means no limit.
+	MaxDepth int
+}
+
+// Value formats v in a format similar to Go syntax, one element or field per
+// line. The output is deterministic so that it can be stored and compared to
+// later output. Map keys are sorted, pointers are printed as & followed by the
+// value they point to instead of their address, ex: &{Name: "a"}, struct
+// fields with a zero value are omitted, and types with a String or Error
+// method are printed as the type and the result of the method, ex:
+// time.Time("2020-01-02 03:04:05 +0000 UTC"). The output is not always valid
+// Go.
+func Value(v interface{}, conf ValueConfig) string {
+	p := &valuePrinter{conf: conf, buf: new(bytes.Buffer), visited: map[visitKey]bool{}}
+	p.print(reflect.ValueOf(v), 0, true)
+	p.buf.WriteString("\n")
+	return p.buf.String()
+}
+
+type valuePrinter struct {
+	conf    ValueConfig
+	buf     *bytes.Buffer
+	visited map[visitKey]bool
+}
+
+// visitKey identifies a pointer, map, or slice that is being printed, to
+// detect values that contain themselves.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter marks value as being printed. It returns false if value is already
+// being printed by a parent, which means the value contains itself.
+func (p *valuePrinter) enter(value reflect.Value) (leave func(), ok bool) {
+	key := visitKey{ptr: value.Pointer(), typ: value.Type()}
+	if value.Kind() == reflect.Slice {
+		key.len = value.Len()
+	}
+	if p.visited[key] {
+		return nil, false
+	}
+	p.visited[key] = true
+	return func() { delete(p.visited, key) }, true
+}
+
+func (p *valuePrinter) write(s string) {
+	p.buf.WriteString(s)
+}
+
+func (p *valuePrinter) newline(depth int) {
+	p.write("\n" + strings.Repeat("\t", depth))
+}
+
+// print writes value at depth. withType is false when the type is already
+// known from the parent, like the elements of a slice.
+func (p *valuePrinter) print(value reflect.Value, depth int, withType bool) {
+	if !value.IsValid() {
+		p.write("nil")
+		return
+	}
+	if value.Kind() == reflect.Interface {
+		p.print(value.Elem(), depth, true)
+		return
+	}
+	if s, ok := stringMethod(value); ok {
+		p.write(value.Type().String() + "(" + strconv.Quote(s) + ")")
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		p.printTyped(value, withType, strconv.Quote(value.String()))
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		p.printTyped(value, withType, fmt.Sprint(value))
+	case reflect.Ptr:
+		p.printPointer(value, depth, withType)
+	case reflect.Slice, reflect.Map:
+		if value.IsNil() {
+			p.write(value.Type().String() + "(nil)")
+			return
+		}
+		leave, ok := p.enter(value)
+		if !ok {
+			p.write("<cycle>")
+			return
+		}
+		defer leave()
+		p.printComposite(value, depth, withType)
+	case reflect.Array, reflect.Struct:
+		p.printComposite(value, depth, withType)
+	default:
+		// func, chan, and unsafe.Pointer can only be compared by address
+		state := "nil"
+		if !value.IsNil() {
+			state = "non-nil"
+		}
+		p.write(value.Type().String() + "(" + state + ")")
+	}
+}
+
+// stringMethod returns the value from the Error or String method of value.
+func stringMethod(value reflect.Value) (string, bool) {
+	if !value.CanInterface() || isNilPointer(value) {
+		return "", false
+	}
+	switch typed := value.Interface().(type) {
+	case error:
+		return typed.Error(), true
+	case fmt.Stringer:
+		return typed.String(), true
+	}
+	return "", false
+}
+
+func isNilPointer(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+func (p *valuePrinter) printTyped(value reflect.Value, withType bool, s string) {
+	if withType && !IsDefaultType(value.Type()) {
+		p.write(value.Type().String() + "(" + s + ")")
+		return
+	}
+	p.write(s)
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	return kind > reflect.Invalid && kind <= reflect.Complex128 || kind == reflect.String
+}
+
+// IsDefaultType returns true if typ is the default type of an untyped constant
+// of its kind, so the type can be omitted from a literal of the type.
+func IsDefaultType(typ reflect.Type) bool {
+	if typ.PkgPath() != "" {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+func (p *valuePrinter) printPointer(value reflect.Value, depth int, withType bool) {
+	if value.IsNil() {
+		p.write("(" + value.Type().String() + ")(nil)")
+		return
+	}
+	leave, ok := p.enter(value)
+	if !ok {
+		p.write("&<cycle>")
+		return
+	}
+	defer leave()
+
+	p.write("&")
+	p.print(value.Elem(), depth, withType)
+}
+
+func (p *valuePrinter) printComposite(value reflect.Value, depth int, withType bool) {
+	if withType {
+		p.write(value.Type().String())
+	}
+	if compositeLen(value) == 0 {
+		p.write("{}")
+		return
+	}
+	if p.conf.MaxDepth > 0 && depth >= p.conf.MaxDepth {
+		p.write("{...}")
+		return
+	}
+
+	p.write("{")
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemIsInterface := value.Type().Elem().Kind() == reflect.Interface
+		for i := 0; i < value.Len(); i++ {
+			p.newline(depth + 1)
+			p.print(value.Index(i), depth+1, elemIsInterface)
+			p.write(",")
+		}
+	case reflect.Map:
+		p.printMapEntries(value, depth)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).IsZero() {
+				continue
+			}
+			field := value.Type().Field(i)
+			p.newline(depth + 1)
+			p.write(field.Name + ": ")
+			// the type of basic values is known from the struct definition,
+			// but the type of composite values is included for readability.
+			p.print(value.Field(i), depth+1, !isBasicKind(field.Type.Kind()))
+			p.write(",")
+		}
+	}
+	p.newline(depth)
+	p.write("}")
+}
+
+// compositeLen returns the number of elements in value. For structs it is
+// the number of fields which are not the zero value.
+func compositeLen(value reflect.Value) int {
+	if value.Kind() != reflect.Struct {
+		return value.Len()
+	}
+	var count int
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsZero() {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *valuePrinter) printMapEntries(value reflect.Value, depth int) {
+	keyIsInterface := value.Type().Key().Kind() == reflect.Interface
+	elemIsInterface := value.Type().Elem().Kind() == reflect.Interface
+
+	type entry struct {
+		key       reflect.Value
+		formatted string
+	}
+	entries := make([]entry, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keyPrinter := &valuePrinter{conf: p.conf, buf: new(bytes.Buffer), visited: p.visited}
+		keyPrinter.print(key, depth+1, keyIsInterface)
+		entries = append(entries, entry{key: key, formatted: keyPrinter.buf.String()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if less, ok := lessKey(entries[i].key, entries[j].key); ok {
+			return less
+		}
+		return entries[i].formatted < entries[j].formatted
+	})
+
+	for _, entry := range entries {
+		p.newline(depth + 1)
+		p.write(entry.formatted + ": ")
+		p.print(value.MapIndex(entry.key), depth+1, elemIsInterface)
+		p.write(",")
+	}
+}
+
+// lessKey compares map keys of the same numeric kind by value, so that
+// numbers are sorted numerically instead of by their formatted string.
+func lessKey(x, y reflect.Value) (less bool, ok bool) {
+	if x.Kind() != y.Kind() {
+		return false, false
+	}
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() < y.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() < y.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return x.Float() < y.Float(), true
+	}
+	return false, false
+}
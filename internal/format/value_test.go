@@ -0,0 +1,109 @@
+package format_test
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/internal/format"
+)
+
+type node struct {
+	Name     string
+	Next     *node
+	Values   map[int]interface{}
+	Callback func()
+	private  uint8
+}
+
+func TestValue(t *testing.T) {
+	var testcases = []struct {
+		name     string
+		value    interface{}
+		maxDepth int
+		expected string
+	}{
+		{name: "nil", value: nil, expected: "nil\n"},
+		{name: "string", value: "a\nb", expected: "\"a\\nb\"\n"},
+		{name: "int8", value: int8(3), expected: "int8(3)\n"},
+		{name: "error", value: errors.New("oops"), expected: "*errors.errorString(\"oops\")\n"},
+		{name: "empty struct", value: node{}, expected: "format_test.node{}\n"},
+		{
+			name:  "map with numeric keys",
+			value: map[int]interface{}{10: "ten", 2: uint(2), 1: nil},
+			expected: `map[int]interface {}{
+	1: nil,
+	2: uint(2),
+	10: "ten",
+}
+`,
+		},
+		{
+			name: "nested pointers",
+			value: &node{
+				Name:     "first",
+				Next:     &node{Name: "second", private: 2},
+				Callback: func() {},
+			},
+			expected: `&format_test.node{
+	Name: "first",
+	Next: &format_test.node{
+		Name: "second",
+		private: 2,
+	},
+	Callback: func()(non-nil),
+}
+`,
+		},
+		{
+			name:     "max depth",
+			value:    []node{{Name: "first", Next: &node{Name: "second"}}},
+			maxDepth: 2,
+			expected: `[]format_test.node{
+	{
+		Name: "first",
+		Next: &format_test.node{...},
+	},
+}
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := format.Value(tc.value, format.ValueConfig{MaxDepth: tc.maxDepth})
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestValue_Cycle(t *testing.T) {
+	value := &node{Name: "loop"}
+	value.Next = value
+	expected := `&format_test.node{
+	Name: "loop",
+	Next: &<cycle>,
+}
+`
+	assert.Equal(t, format.Value(value, format.ValueConfig{}), expected)
+}
+
+func TestValue_CycleThroughInterface(t *testing.T) {
+	m := map[string]interface{}{"name": "loop"}
+	m["self"] = m
+	expected := `map[string]interface {}{
+	"name": "loop",
+	"self": <cycle>,
+}
+`
+	assert.Equal(t, format.Value(m, format.ValueConfig{}), expected)
+
+	s := []interface{}{"loop", nil}
+	s[1] = s
+	expected = `[]interface {}{
+	"loop",
+	<cycle>,
+}
+`
+	assert.Equal(t, format.Value(s, format.ValueConfig{}), expected)
+}
@@ -0,0 +1,155 @@
+// Package registry stores the comparers and formatters that are registered for
+// types with gotest.tools/v3/assert.RegisterComparer and RegisterFormatter.
+package registry // import "gotest.tools/v3/internal/registry"
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var registry = struct {
+	sync.RWMutex
+	comparers  map[reflect.Type]reflect.Value
+	formatters map[reflect.Type]reflect.Value
+}{
+	comparers:  make(map[reflect.Type]reflect.Value),
+	formatters: make(map[reflect.Type]reflect.Value),
+}
+
+var (
+	boolType   = reflect.TypeOf(true)
+	stringType = reflect.TypeOf("")
+)
+
+// RegisterComparer adds compare as the comparer for values of type T. compare
+// must be a func(x, y T) bool. A comparer replaces any previous comparer for
+// the same type.
+func RegisterComparer(compare interface{}) error {
+	fn := reflect.ValueOf(compare)
+	typ := reflect.TypeOf(compare)
+	if typ == nil {
+		return fmt.Errorf("invalid comparer nil, must be func(x, y T) bool")
+	}
+	if typ.Kind() != reflect.Func || typ.NumIn() != 2 || typ.In(0) != typ.In(1) ||
+		typ.NumOut() != 1 || typ.Out(0) != boolType || fn.IsNil() {
+		return fmt.Errorf("invalid comparer %s, must be func(x, y T) bool", typ)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.comparers[typ.In(0)] = fn
+	return nil
+}
+
+// RegisterFormatter adds format as the formatter for values of type T. format
+// must be a func(T) string. A formatter replaces any previous formatter for
+// the same type.
+func RegisterFormatter(format interface{}) error {
+	fn := reflect.ValueOf(format)
+	typ := reflect.TypeOf(format)
+	if typ == nil {
+		return fmt.Errorf("invalid formatter nil, must be func(T) string")
+	}
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 ||
+		typ.NumOut() != 1 || typ.Out(0) != stringType || fn.IsNil() {
+		return fmt.Errorf("invalid formatter %s, must be func(T) string", typ)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.formatters[typ.In(0)] = fn
+	return nil
+}
+
+// Compare x and y with the comparer registered for their type. The comparer
+// for type T applies when the types of both x and y are assignable to T, so a
+// comparer for an interface type applies to all the types that implement it.
+// ok is false if there is no comparer for x and y. An error is returned if
+// more than one comparer applies, like DeepEqual which panics in that case.
+func Compare(x, y interface{}) (equal bool, ok bool, err error) {
+	typX, typY := reflect.TypeOf(x), reflect.TypeOf(y)
+	if typX == nil || typY == nil {
+		return false, false, nil
+	}
+	registry.RLock()
+	var matches []reflect.Type
+	var fn reflect.Value
+	for typ, comparer := range registry.comparers {
+		if typX.AssignableTo(typ) && typY.AssignableTo(typ) {
+			matches = append(matches, typ)
+			fn = comparer
+		}
+	}
+	registry.RUnlock()
+	switch len(matches) {
+	case 0:
+		return false, false, nil
+	case 1:
+	default:
+		names := make([]string, 0, len(matches))
+		for _, typ := range matches {
+			names = append(names, typ.String())
+		}
+		sort.Strings(names)
+		return false, false, fmt.Errorf("more than one comparer applies to %s and %s: %s",
+			typX, typY, strings.Join(names, ", "))
+	}
+	out := fn.Call([]reflect.Value{
+		reflect.ValueOf(x).Convert(matches[0]),
+		reflect.ValueOf(y).Convert(matches[0]),
+	})
+	return out[0].Bool(), true, nil
+}
+
+// Comparers returns all the registered comparers.
+func Comparers() []interface{} {
+	registry.RLock()
+	defer registry.RUnlock()
+	result := make([]interface{}, 0, len(registry.comparers))
+	for _, fn := range registry.comparers {
+		result = append(result, fn.Interface())
+	}
+	return result
+}
+
+// HasFormatter returns true if there is a formatter registered for typ, or for
+// an interface implemented by typ.
+func HasFormatter(typ reflect.Type) bool {
+	_, ok := formatter(typ)
+	return ok
+}
+
+// Format v with the formatter registered for its type. If there is no
+// formatter for the type, the formatter for an interface implemented by the
+// type is used. ok is false if there is no formatter, or if the type
+// implements more than one interface which has a formatter.
+func Format(v interface{}) (string, bool) {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return "", false
+	}
+	fn, ok := formatter(typ)
+	if !ok {
+		return "", false
+	}
+	arg := reflect.ValueOf(v).Convert(fn.Type().In(0))
+	return fn.Call([]reflect.Value{arg})[0].String(), true
+}
+
+func formatter(typ reflect.Type) (reflect.Value, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if fn, ok := registry.formatters[typ]; ok {
+		return fn, true
+	}
+	var match reflect.Value
+	var count int
+	for iface, fn := range registry.formatters {
+		if iface.Kind() == reflect.Interface && typ.Implements(iface) {
+			match = fn
+			count++
+		}
+	}
+	return match, count == 1
+}
@@ -0,0 +1,132 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type celsius float64
+
+func TestCompare(t *testing.T) {
+	if err := RegisterComparer(func(x, y celsius) bool { return int(x) == int(y) }); err != nil {
+		t.Fatal(err)
+	}
+
+	equal, ok, err := Compare(celsius(20.1), celsius(20.9))
+	if !ok || !equal || err != nil {
+		t.Fatalf("expected equal values, got equal=%v ok=%v err=%v", equal, ok, err)
+	}
+	equal, ok, err = Compare(celsius(20.1), celsius(21.1))
+	if !ok || equal || err != nil {
+		t.Fatalf("expected values to not be equal, got equal=%v ok=%v err=%v", equal, ok, err)
+	}
+	if _, ok, _ := Compare(celsius(20), 20.0); ok {
+		t.Fatal("expected no comparer for different types")
+	}
+	if _, ok, _ := Compare(nil, nil); ok {
+		t.Fatal("expected no comparer for nil")
+	}
+}
+
+type named interface {
+	Name() string
+}
+
+type user string
+
+func (u user) Name() string { return string(u) }
+
+type group string
+
+func (g group) Name() string { return string(g) }
+
+func TestCompare_Interface(t *testing.T) {
+	err := RegisterComparer(func(x, y named) bool {
+		return strings.EqualFold(x.Name(), y.Name())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.comparers, reflect.TypeOf((*named)(nil)).Elem())
+		delete(registry.comparers, reflect.TypeOf(user("")))
+	})
+
+	equal, ok, err := Compare(user("Admin"), user("admin"))
+	if !ok || !equal || err != nil {
+		t.Fatalf("expected equal values, got equal=%v ok=%v err=%v", equal, ok, err)
+	}
+	equal, ok, err = Compare(user("admin"), group("ADMIN"))
+	if !ok || !equal || err != nil {
+		t.Fatalf("expected equal values, got equal=%v ok=%v err=%v", equal, ok, err)
+	}
+
+	if err := RegisterComparer(func(x, y user) bool { return x == y }); err != nil {
+		t.Fatal(err)
+	}
+	_, ok, err = Compare(user("Admin"), user("admin"))
+	expected := "more than one comparer applies to registry.user and registry.user: " +
+		"registry.named, registry.user"
+	if ok || err == nil || err.Error() != expected {
+		t.Fatalf("expected an error for more than one comparer, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if err := RegisterFormatter(func(c celsius) string { return "20°C" }); err != nil {
+		t.Fatal(err)
+	}
+	if !HasFormatter(reflect.TypeOf(celsius(0))) {
+		t.Fatal("expected a formatter for celsius")
+	}
+	if s, ok := Format(celsius(20)); !ok || s != "20°C" {
+		t.Fatalf("expected formatted value, got %q ok=%v", s, ok)
+	}
+	if _, ok := Format(20); ok {
+		t.Fatal("expected no formatter for int")
+	}
+}
+
+func TestFormat_Interface(t *testing.T) {
+	if err := RegisterFormatter(func(n named) string { return "name:" + n.Name() }); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.formatters, reflect.TypeOf((*named)(nil)).Elem())
+		delete(registry.formatters, reflect.TypeOf(group("")))
+	})
+
+	if s, ok := Format(user("admin")); !ok || s != "name:admin" {
+		t.Fatalf("expected formatted value, got %q ok=%v", s, ok)
+	}
+	if !HasFormatter(reflect.TypeOf(group(""))) {
+		t.Fatal("expected a formatter for group")
+	}
+	if err := RegisterFormatter(func(g group) string { return "group:" + string(g) }); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := Format(group("admin")); !ok || s != "group:admin" {
+		t.Fatalf("expected the formatter for the type, got %q ok=%v", s, ok)
+	}
+}
+
+func TestRegister_InvalidSignature(t *testing.T) {
+	for _, fn := range []interface{}{
+		func(x celsius, y float64) bool { return false },
+		func(x, y celsius) {},
+		"not a func",
+		nil,
+	} {
+		if err := RegisterComparer(fn); err == nil {
+			t.Errorf("expected an error for comparer %T", fn)
+		}
+	}
+	if err := RegisterFormatter(func(celsius) int { return 0 }); err == nil {
+		t.Error("expected an error for formatter")
+	}
+}
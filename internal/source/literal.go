@@ -0,0 +1,372 @@
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gotest.tools/v3/internal/format"
+)
+
+// literalPrinter converts values to ast.Expr that can be written to a Go source
+// file.
+type literalPrinter struct {
+	// pkgPath is the import path of the package of the file that will be
+	// updated. Types from this package are printed without a package qualifier.
+	pkgPath string
+	// imports maps the path of each package imported by the file to the name
+	// used for the package in the file.
+	imports map[string]string
+}
+
+// newLiteralPrinter returns a literalPrinter for astFile, which is a file in
+// the package with import path pkgPath.
+func newLiteralPrinter(astFile *ast.File, pkgPath string) literalPrinter {
+	p := literalPrinter{pkgPath: pkgPath, imports: make(map[string]string)}
+	for _, spec := range astFile.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		p.imports[path] = name
+	}
+	return p
+}
+
+// literalContext describes the position where the literal will be written.
+type literalContext struct {
+	// typed is true when the static type at the position is the same as the
+	// type of the value. When false, a conversion is added to any literal that
+	// would otherwise have a different default type.
+	typed bool
+	// elide is true when the type of a composite literal may be omitted,
+	// because it is the element of a slice, array, or map literal.
+	elide bool
+}
+
+func (p literalPrinter) expr(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	if !value.IsValid() {
+		return ast.NewIdent("nil"), nil
+	}
+	typ := value.Type()
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return p.convert(ast.NewIdent(strconv.FormatBool(value.Bool())), typ, ctx)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit := &ast.BasicLit{Kind: token.INT, Value: strconv.FormatInt(value.Int(), 10)}
+		return p.convert(lit, typ, ctx)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lit := &ast.BasicLit{Kind: token.INT, Value: strconv.FormatUint(value.Uint(), 10)}
+		return p.convert(lit, typ, ctx)
+	case reflect.Float32, reflect.Float64:
+		return p.float(value, ctx)
+	case reflect.String:
+		return p.convert(stringLiteral(value.String()), typ, ctx)
+	case reflect.Interface:
+		if value.IsNil() {
+			return ast.NewIdent("nil"), nil
+		}
+		return p.expr(value.Elem(), literalContext{})
+	case reflect.Ptr:
+		return p.pointer(value, ctx)
+	case reflect.Slice:
+		if value.IsNil() {
+			return p.typedNil(typ, ctx)
+		}
+		return p.sequence(value, ctx)
+	case reflect.Array:
+		return p.sequence(value, ctx)
+	case reflect.Map:
+		if value.IsNil() {
+			return p.typedNil(typ, ctx)
+		}
+		return p.mapping(value, ctx)
+	case reflect.Struct:
+		return p.structure(value, ctx)
+	}
+	return nil, fmt.Errorf("values of kind %s are not supported", value.Kind())
+}
+
+func (p literalPrinter) float(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	f := value.Float()
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("float value %v is not supported", f)
+	}
+	bitSize := 64
+	if value.Kind() == reflect.Float32 {
+		bitSize = 32
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return p.convert(&ast.BasicLit{Kind: token.FLOAT, Value: s}, value.Type(), ctx)
+}
+
+// convert wraps lit in a conversion to typ when the default type of lit is
+// not the same as typ.
+func (p literalPrinter) convert(
+	lit ast.Expr,
+	typ reflect.Type,
+	ctx literalContext,
+) (ast.Expr, error) {
+	if ctx.typed || format.IsDefaultType(typ) {
+		return lit, nil
+	}
+	typeExpr, err := p.typeExpr(typ)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := typeExpr.(*ast.Ident); !ok {
+		typeExpr = &ast.ParenExpr{X: typeExpr}
+	}
+	return &ast.CallExpr{Fun: typeExpr, Args: []ast.Expr{lit}}, nil
+}
+
+func (p literalPrinter) typedNil(typ reflect.Type, ctx literalContext) (ast.Expr, error) {
+	return p.convert(ast.NewIdent("nil"), typ, ctx)
+}
+
+func (p literalPrinter) pointer(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	if value.IsNil() {
+		return p.typedNil(value.Type(), ctx)
+	}
+	if value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("pointers to %s are not supported", value.Elem().Kind())
+	}
+	elem, err := p.structure(value.Elem(), literalContext{elide: ctx.elide})
+	if err != nil || ctx.elide {
+		return elem, err
+	}
+	return &ast.UnaryExpr{Op: token.AND, X: elem}, nil
+}
+
+func (p literalPrinter) composite(typ reflect.Type, ctx literalContext) (*ast.CompositeLit, error) {
+	lit := &ast.CompositeLit{}
+	if ctx.elide {
+		return lit, nil
+	}
+	typeExpr, err := p.typeExpr(typ)
+	lit.Type = typeExpr
+	return lit, err
+}
+
+// elemContext returns the context for an element of a composite literal with
+// element type elemType.
+func elemContext(elemType reflect.Type, value reflect.Value) literalContext {
+	if elemType.Kind() == reflect.Interface {
+		return literalContext{}
+	}
+	return literalContext{typed: value.Type() == elemType, elide: true}
+}
+
+func (p literalPrinter) sequence(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	lit, err := p.composite(value.Type(), ctx)
+	if err != nil {
+		return nil, err
+	}
+	elemType := value.Type().Elem()
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		elt, err := p.expr(item, elemContext(elemType, item))
+		if err != nil {
+			return nil, err
+		}
+		lit.Elts = append(lit.Elts, elt)
+	}
+	return lit, nil
+}
+
+func (p literalPrinter) mapping(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	lit, err := p.composite(value.Type(), ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyType, elemType := value.Type().Key(), value.Type().Elem()
+	for _, key := range value.MapKeys() {
+		keyExpr, err := p.expr(key, elemContext(keyType, key))
+		if err != nil {
+			return nil, err
+		}
+		item := value.MapIndex(key)
+		valueExpr, err := p.expr(item, elemContext(elemType, item))
+		if err != nil {
+			return nil, err
+		}
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{Key: keyExpr, Value: valueExpr})
+	}
+	// sort the keys so that the output is stable
+	sort.Slice(lit.Elts, func(i, j int) bool {
+		return formatKey(lit.Elts[i]) < formatKey(lit.Elts[j])
+	})
+	return lit, nil
+}
+
+func formatKey(expr ast.Expr) string {
+	out, _ := FormatNode(expr.(*ast.KeyValueExpr).Key)
+	return out
+}
+
+func (p literalPrinter) structure(value reflect.Value, ctx literalContext) (ast.Expr, error) {
+	typ := value.Type()
+	lit, err := p.composite(typ, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		item := value.Field(i)
+		if item.IsZero() {
+			continue
+		}
+		if field.PkgPath != "" && !p.isLocalType(typ) {
+			return nil, fmt.Errorf("unexported field %s of %s can not be set", field.Name, typ)
+		}
+
+		fieldCtx := literalContext{typed: field.Type.Kind() != reflect.Interface}
+		valueExpr, err := p.expr(item, fieldCtx)
+		if err != nil {
+			return nil, err
+		}
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent(field.Name),
+			Value: valueExpr,
+		})
+	}
+	return lit, nil
+}
+
+func (p literalPrinter) isLocalType(typ reflect.Type) bool {
+	return p.pkgPath != "" && typ.PkgPath() == p.pkgPath
+}
+
+// typeExpr returns the expression for typ in the file. Types from other
+// packages are qualified with the name used for the package in the imports of
+// the file. An error is returned if the package is not imported.
+func (p literalPrinter) typeExpr(typ reflect.Type) (ast.Expr, error) {
+	if typ.Name() != "" {
+		return p.namedType(typ)
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elem, err := p.typeExpr(typ.Elem())
+		return &ast.StarExpr{X: elem}, err
+	case reflect.Slice:
+		elem, err := p.typeExpr(typ.Elem())
+		return &ast.ArrayType{Elt: elem}, err
+	case reflect.Array:
+		elem, err := p.typeExpr(typ.Elem())
+		length := &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(typ.Len())}
+		return &ast.ArrayType{Len: length, Elt: elem}, err
+	case reflect.Map:
+		key, err := p.typeExpr(typ.Key())
+		if err != nil {
+			return nil, err
+		}
+		elem, err := p.typeExpr(typ.Elem())
+		return &ast.MapType{Key: key, Value: elem}, err
+	case reflect.Interface:
+		if typ.NumMethod() == 0 {
+			return parser.ParseExpr("interface{}")
+		}
+	case reflect.Struct:
+		return p.structType(typ)
+	}
+	return nil, fmt.Errorf("type %s is not supported", typ)
+}
+
+func (p literalPrinter) namedType(typ reflect.Type) (ast.Expr, error) {
+	name := typ.Name()
+	if typ.PkgPath() == "" {
+		// predeclared types, like int and error
+		return ast.NewIdent(name), nil
+	}
+	if strings.Contains(name, "[") {
+		return nil, fmt.Errorf("generic type %s is not supported", typ)
+	}
+	if p.isLocalType(typ) {
+		return ast.NewIdent(name), nil
+	}
+
+	importName, ok := p.imports[typ.PkgPath()]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("package %s of type %s is not imported", typ.PkgPath(), typ)
+	case importName == "_":
+		return nil, fmt.Errorf("package %s of type %s is imported with a blank name",
+			typ.PkgPath(), typ)
+	case importName == ".":
+		return ast.NewIdent(name), nil
+	case importName == "":
+		// the default name of the import is the name of the package, which
+		// is the qualifier used by reflect.
+		importName = strings.TrimSuffix(typ.String(), "."+name)
+	}
+	return &ast.SelectorExpr{X: ast.NewIdent(importName), Sel: ast.NewIdent(name)}, nil
+}
+
+func (p literalPrinter) structType(typ reflect.Type) (ast.Expr, error) {
+	fields := &ast.FieldList{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && field.PkgPath != p.pkgPath {
+			return nil, fmt.Errorf("unexported field %s of %s can not be set", field.Name, typ)
+		}
+		fieldType, err := p.typeExpr(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		astField := &ast.Field{Type: fieldType}
+		if !field.Anonymous {
+			astField.Names = []*ast.Ident{ast.NewIdent(field.Name)}
+		}
+		if field.Tag != "" {
+			astField.Tag = &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(string(field.Tag))}
+		}
+		fields.List = append(fields.List, astField)
+	}
+	return &ast.StructType{Fields: fields}, nil
+}
+
+// stringLiteral returns a raw string literal for multi-line strings, and an
+// interpreted string literal for all other strings.
+func stringLiteral(value string) *ast.BasicLit {
+	if strings.Contains(value, "\n") && isRawStringSafe(value) {
+		return &ast.BasicLit{Kind: token.STRING, Value: "`" + value + "`"}
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(value)}
+}
+
+func isRawStringSafe(value string) bool {
+	return !strings.ContainsAny(value, "`\r")
+}
+
+// isType returns true if typeExpr is the type of value.
+func (p literalPrinter) isType(typeExpr ast.Expr, value reflect.Value) bool {
+	if !value.IsValid() {
+		return false
+	}
+	expected, err := p.typeExpr(value.Type())
+	if err != nil {
+		return false
+	}
+	return sameSource(typeExpr, expected)
+}
+
+// sameSource returns true if a and b are formatted to the same source.
+func sameSource(a, b ast.Node) bool {
+	sourceA, errA := FormatNode(a)
+	sourceB, errB := FormatNode(b)
+	return errA == nil && errB == nil && sourceA == sourceB
+}
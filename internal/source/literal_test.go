@@ -0,0 +1,161 @@
+package source
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+type stubStruct struct {
+	Name   string
+	Count  uint8
+	Tags   []string
+	Nested *stubStruct
+	Any    interface{}
+	hidden bool
+}
+
+type stubString string
+
+func TestLiteralPrinter(t *testing.T) {
+	var testcases = []struct {
+		name     string
+		value    interface{}
+		ctx      literalContext
+		expected string
+	}{
+		{name: "bool", value: true, expected: `true`},
+		{name: "int", value: -12, expected: `-12`},
+		{name: "int64", value: int64(12), expected: `int64(12)`},
+		{name: "int64 typed", value: int64(12), ctx: literalContext{typed: true}, expected: `12`},
+		{name: "float64", value: 2.0, expected: `2.0`},
+		{name: "float32", value: float32(1.5), expected: `float32(1.5)`},
+		{name: "string", value: "a \"b\"", expected: `"a \"b\""`},
+		{name: "multi-line string", value: "a\nb", expected: "`a\nb`"},
+		{name: "named string", value: stubString("x"), expected: `stubString("x")`},
+		{name: "nil slice", value: []int(nil), expected: `([]int)(nil)`},
+		{name: "slice", value: []interface{}{1, "a"}, expected: `[]interface{}{1, "a"}`},
+		{
+			name:     "map with sorted keys",
+			value:    map[string]uint{"b": 2, "a": 1},
+			expected: `map[string]uint{"a": 1, "b": 2}`,
+		},
+		{
+			name: "struct",
+			value: &stubStruct{
+				Name:   "first",
+				Count:  3,
+				Tags:   []string{"x"},
+				Nested: &stubStruct{Any: int8(1)},
+				hidden: true,
+			},
+			expected: `&stubStruct{Name: "first", Count: 3, Tags: []string{"x"}, ` +
+				`Nested: &stubStruct{Any: int8(1)}, hidden: true}`,
+		},
+		{
+			name:     "slice of pointers",
+			value:    []*stubStruct{{Name: "a"}},
+			expected: `[]*stubStruct{{Name: "a"}}`,
+		},
+	}
+
+	printer := literalPrinter{pkgPath: "gotest.tools/v3/internal/source"}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := printer.expr(reflect.ValueOf(tc.value), tc.ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := FormatNode(expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected\n%s\ngot\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLiteralPrinter_Unsupported(t *testing.T) {
+	printer := literalPrinter{
+		pkgPath: "example.com/other",
+		imports: map[string]string{"gotest.tools/v3/internal/source": ""},
+	}
+	_, err := printer.expr(reflect.ValueOf(stubStruct{hidden: true}), literalContext{})
+	if err == nil || err.Error() != "unexported field hidden of source.stubStruct can not be set" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = printer.expr(reflect.ValueOf(make(chan int)), literalContext{})
+	if err == nil || err.Error() != "values of kind chan are not supported" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLiteralPrinter_ImportedTypes(t *testing.T) {
+	var testcases = []struct {
+		name     string
+		imports  map[string]string
+		expected string
+		err      string
+	}{
+		{
+			name:     "default name",
+			imports:  map[string]string{"gotest.tools/v3/internal/source": ""},
+			expected: `[]source.stubString{"a"}`,
+		},
+		{
+			name:     "aliased",
+			imports:  map[string]string{"gotest.tools/v3/internal/source": "src"},
+			expected: `[]src.stubString{"a"}`,
+		},
+		{
+			name:     "dot import",
+			imports:  map[string]string{"gotest.tools/v3/internal/source": "."},
+			expected: `[]stubString{"a"}`,
+		},
+		{
+			name:    "not imported",
+			imports: map[string]string{"example.com/source": ""},
+			err: "package gotest.tools/v3/internal/source of type source.stubString " +
+				"is not imported",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			printer := literalPrinter{pkgPath: "example.com/other", imports: tc.imports}
+			expr, err := printer.expr(reflect.ValueOf([]stubString{"a"}), literalContext{})
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := FormatNode(expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected\n%s\ngot\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCallerPackagePath(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	if actual := callerPackagePath(pc); actual != "gotest.tools/v3/internal/source" {
+		t.Fatalf("unexpected package path: %s", actual)
+	}
+	func() {
+		pc, _, _, _ := runtime.Caller(0)
+		if actual := callerPackagePath(pc); actual != "gotest.tools/v3/internal/source" {
+			t.Fatalf("unexpected package path: %s", actual)
+		}
+	}()
+}
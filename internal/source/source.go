@@ -30,6 +30,15 @@ func FormattedCallExprArg(stackIndex int, argPos int) (string, error) {
 // CallExprArgs returns the ast.Expr slice for the args of an ast.CallExpr at
 // the index in the call stack.
 func CallExprArgs(stackIndex int) ([]ast.Expr, error) {
+	expr, err := CallExpr(stackIndex + 1)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Args, nil
+}
+
+// CallExpr returns the ast.CallExpr at the index in the call stack.
+func CallExpr(stackIndex int) (*ast.CallExpr, error) {
 	_, filename, line, ok := runtime.Caller(stackIndex + 1)
 	if !ok {
 		return nil, errors.New("failed to get call stack")
@@ -55,7 +64,7 @@ func CallExprArgs(stackIndex int) ([]ast.Expr, error) {
 		return nil, fmt.Errorf("failed to parse source file %s: %w", filename, err)
 	}
 
-	expr, err := getCallExprArgs(fileset, astFile, line)
+	expr, err := getCallExpr(fileset, astFile, line)
 	if err != nil {
 		return nil, fmt.Errorf("call from %s:%d: %w", filename, line, err)
 	}
@@ -91,6 +100,14 @@ func scanToLine(fileset *token.FileSet, node ast.Node, lineNum int) ast.Node {
 }
 
 func getCallExprArgs(fileset *token.FileSet, astFile ast.Node, line int) ([]ast.Expr, error) {
+	expr, err := getCallExpr(fileset, astFile, line)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Args, nil
+}
+
+func getCallExpr(fileset *token.FileSet, astFile ast.Node, line int) (*ast.CallExpr, error) {
 	node, err := getNodeAtLine(fileset, astFile, line)
 	if err != nil {
 		return nil, err
@@ -104,7 +121,7 @@ func getCallExprArgs(fileset *token.FileSet, astFile ast.Node, line int) ([]ast.
 		return nil, errors.New("failed to find an expression")
 	}
 	debug("callExpr: %s", debugFormatNode{visitor.expr})
-	return visitor.expr.Args, nil
+	return visitor.expr, nil
 }
 
 type callExprVisitor struct {
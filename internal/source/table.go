@@ -0,0 +1,248 @@
+package source
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tableField is a field of the test case in a table test, where the test case
+// is the value of a range statement over a composite literal.
+//
+//	testcases := []struct{ input, expected string }{...}
+//	for _, tc := range testcases {
+//	    assert.Equal(t, do(tc.input), tc.expected)
+//	}
+type tableField struct {
+	name  string
+	table *ast.CompositeLit
+}
+
+func getTableFieldForExpectedValueArg(expr []ast.Expr) (int, *tableField) {
+	var index int
+	var found *tableField
+	for i := 1; i < 3; i++ {
+		field := getTableField(expr[i])
+		switch {
+		case field == nil:
+			continue
+		case isExpectedName(field.name):
+			return i, field
+		case found == nil:
+			index, found = i, field
+		default:
+			debug("more than one table field, and neither starts with expected")
+			return -1, nil
+		}
+	}
+	return index, found
+}
+
+func isExpectedName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "expected") || strings.HasPrefix(name, "want")
+}
+
+func getTableField(expr ast.Expr) *tableField {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	table := rangeCompositeLit(ident)
+	if table == nil {
+		return nil
+	}
+	return &tableField{name: selector.Sel.Name, table: table}
+}
+
+// rangeCompositeLit returns the composite literal used in the range statement
+// that declares ident as the value variable.
+func rangeCompositeLit(ident *ast.Ident) *ast.CompositeLit {
+	// follow tc := tc, which is used to capture the range variable
+	for i := 0; i < 2; i++ {
+		if ident.Obj == nil {
+			return nil
+		}
+		assign, ok := ident.Obj.Decl.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return nil
+		}
+
+		switch rhs := assign.Rhs[0].(type) {
+		case *ast.Ident:
+			ident = rhs
+		case *ast.UnaryExpr:
+			// The parser declares range variables with an AssignStmt where the
+			// Rhs is a UnaryExpr with the range token.
+			if rhs.Op != token.RANGE || len(assign.Lhs) != 2 {
+				return nil
+			}
+			if lhs, ok := assign.Lhs[1].(*ast.Ident); !ok || lhs.Obj != ident.Obj {
+				return nil
+			}
+			return compositeLitFromExpr(rhs.X)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// compositeLitFromExpr returns expr if it is a composite literal, or the value
+// of the variable if expr is an identifier for a variable that was declared
+// with a composite literal.
+func compositeLitFromExpr(expr ast.Expr) *ast.CompositeLit {
+	switch typed := expr.(type) {
+	case *ast.CompositeLit:
+		return typed
+	case *ast.Ident:
+		if typed.Obj == nil {
+			return nil
+		}
+		var values []ast.Expr
+		switch decl := typed.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			values = decl.Values
+		case *ast.AssignStmt:
+			values = decl.Rhs
+		}
+		if len(values) != 1 {
+			return nil
+		}
+		lit, _ := values[0].(*ast.CompositeLit)
+		return lit
+	}
+	return nil
+}
+
+// update the field in the test case with the current value to value.
+func (f *tableField) update(printer literalPrinter, current, value reflect.Value) error {
+	ctx := literalContext{typed: f.isTyped()}
+	currentExpr, err := printer.expr(current, ctx)
+	if err != nil {
+		debug("failed to create literal for current value: %v", err)
+		return ErrNotFound
+	}
+
+	var matches []*ast.CompositeLit
+	var fields []*ast.KeyValueExpr
+	for _, elt := range f.table.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			// map of test cases
+			elt = kv.Value
+		}
+		if unary, ok := elt.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			elt = unary.X
+		}
+		testcase, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		field, ok := findField(testcase, f.name)
+		switch {
+		case !ok:
+			continue
+		case field == nil && !current.IsZero():
+			continue
+		case field != nil && !matchesValue(field.Value, current, currentExpr):
+			continue
+		}
+		matches = append(matches, testcase)
+		fields = append(fields, field)
+	}
+
+	switch len(matches) {
+	case 0:
+		debug("no test case has field %v with the current value", f.name)
+		return ErrNotFound
+	case 1:
+	default:
+		debug("%d test cases have field %v with the current value", len(matches), f.name)
+		return ErrNotFound
+	}
+
+	lit, err := printer.expr(value, ctx)
+	if err != nil {
+		debug("failed to create literal: %v", err)
+		return ErrNotFound
+	}
+	if fields[0] != nil {
+		fields[0].Value = lit
+		return nil
+	}
+	matches[0].Elts = append(matches[0].Elts, &ast.KeyValueExpr{
+		Key:   ast.NewIdent(f.name),
+		Value: lit,
+	})
+	return nil
+}
+
+// isTyped returns true if the type of the field is known to not be an
+// interface.
+func (f *tableField) isTyped() bool {
+	var elem ast.Expr
+	switch typ := f.table.Type.(type) {
+	case *ast.ArrayType:
+		elem = typ.Elt
+	case *ast.MapType:
+		elem = typ.Value
+	}
+	if star, ok := elem.(*ast.StarExpr); ok {
+		elem = star.X
+	}
+	if ident, ok := elem.(*ast.Ident); ok && ident.Obj != nil {
+		if spec, ok := ident.Obj.Decl.(*ast.TypeSpec); ok {
+			elem = spec.Type
+		}
+	}
+	structType, ok := elem.(*ast.StructType)
+	if !ok {
+		return false
+	}
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			if name.Name != f.name {
+				continue
+			}
+			switch typ := field.Type.(type) {
+			case *ast.InterfaceType:
+				return false
+			case *ast.Ident:
+				return typ.Name != "any"
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// findField returns the field with name from the composite literal, or nil if
+// the field is not set. The bool is false if the literal does not use keyed
+// fields.
+func findField(lit *ast.CompositeLit, name string) (*ast.KeyValueExpr, bool) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+			return kv, true
+		}
+	}
+	return nil, true
+}
+
+// matchesValue returns true if expr is the source form of value.
+func matchesValue(expr ast.Expr, value reflect.Value, valueExpr ast.Expr) bool {
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		unquoted, err := strconv.Unquote(lit.Value)
+		return err == nil && value.Kind() == reflect.String && unquoted == value.String()
+	}
+	return sameSource(expr, valueExpr)
+}
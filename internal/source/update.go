@@ -10,6 +10,7 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 )
@@ -43,15 +44,23 @@ func init() {
 }
 
 // ErrNotFound indicates that UpdateExpectedValue failed to find the
-// variable to update, likely because it is not a package level variable.
+// expression to update, or that the new value can not be written as Go source.
 var ErrNotFound = fmt.Errorf("failed to find variable for update of golden value")
 
-// UpdateExpectedValue looks for a package-level variable with a name that
-// starts with expected in the arguments to the caller. If the variable is
-// found, the value of the variable will be updated to value of the other
-// argument to the caller.
+// UpdateExpectedValue updates the expected value in the arguments to the
+// caller. The expected value is the first of these that is found:
+//
+//   - an identifier for a var or const with a name that starts with expected.
+//     The declaration of the variable is updated.
+//   - a field of a table test case, where the case is the value of a range
+//     statement over a composite literal. The field in the case with the
+//     current expected value is updated.
+//   - a literal value, which is replaced at the call site.
+//
+// The expected value is replaced with the value of the other argument to the
+// caller, formatted as Go syntax.
 func UpdateExpectedValue(stackIndex int, x, y interface{}) error {
-	_, filename, line, ok := runtime.Caller(stackIndex + 1)
+	pc, filename, line, ok := runtime.Caller(stackIndex + 1)
 	if !ok {
 		return errors.New("failed to get call stack")
 	}
@@ -74,24 +83,55 @@ func UpdateExpectedValue(stackIndex int, x, y interface{}) error {
 		return ErrNotFound
 	}
 
-	argIndex, ident := getIdentForExpectedValueArg(expr)
-	if argIndex < 0 || ident == nil {
-		debug("no arguments started with the word 'expected': %v",
-			debugFormatNode{Node: &ast.CallExpr{Args: expr}})
-		return ErrNotFound
+	values := [3]interface{}{nil, x, y}
+	otherValue := func(argIndex int) reflect.Value {
+		return reflect.ValueOf(values[3-argIndex])
 	}
+	printer := newLiteralPrinter(astFile, callerPackagePath(pc))
 
-	value := x
-	if argIndex == 1 {
-		value = y
+	if argIndex, ident := getIdentForExpectedValueArg(expr); ident != nil {
+		return updateVariable(filename, fileset, astFile, ident, printer, otherValue(argIndex))
 	}
 
-	strValue, ok := value.(string)
-	if !ok {
-		debug("value must be type string, got %T", value)
-		return ErrNotFound
+	if argIndex, field := getTableFieldForExpectedValueArg(expr); field != nil {
+		current := reflect.ValueOf(values[argIndex])
+		if err := field.update(printer, current, otherValue(argIndex)); err != nil {
+			return err
+		}
+		return writeFile(filename, fileset, astFile)
+	}
+
+	if argIndex := getLiteralExpectedValueArg(expr); argIndex > 0 {
+		lit, err := printer.expr(otherValue(argIndex), literalContext{})
+		if err != nil {
+			debug("failed to create literal: %v", err)
+			return ErrNotFound
+		}
+		expr[argIndex] = lit
+		return writeFile(filename, fileset, astFile)
 	}
-	return UpdateVariable(filename, fileset, astFile, ident, strValue)
+
+	debug("no arguments are an expected variable, table field, or literal: %v",
+		debugFormatNode{Node: &ast.CallExpr{Args: expr}})
+	return ErrNotFound
+}
+
+// callerPackagePath returns the import path of the package of the function at
+// pc, or an empty string if the function is not found.
+func callerPackagePath(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	// the name is the package path followed by the name of the function, like
+	// example.com/pkg.(*Type).Method.func1
+	name := fn.Name()
+	lastSlash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[lastSlash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return name[:lastSlash+1+dot]
 }
 
 // UpdateVariable writes to filename the contents of astFile with the value of
@@ -102,6 +142,18 @@ func UpdateVariable(
 	astFile *ast.File,
 	ident *ast.Ident,
 	value string,
+) error {
+	printer := newLiteralPrinter(astFile, "")
+	return updateVariable(filename, fileset, astFile, ident, printer, reflect.ValueOf(value))
+}
+
+func updateVariable(
+	filename string,
+	fileset *token.FileSet,
+	astFile *ast.File,
+	ident *ast.Ident,
+	printer literalPrinter,
+	value reflect.Value,
 ) error {
 	obj := ident.Obj
 	if obj == nil {
@@ -112,34 +164,46 @@ func UpdateVariable(
 		return ErrNotFound
 	}
 
+	var typeExpr ast.Expr
+	var values []ast.Expr
 	switch decl := obj.Decl.(type) {
 	case *ast.ValueSpec:
 		if len(decl.Names) != 1 {
 			debug("more than one name in ast.ValueSpec")
 			return ErrNotFound
 		}
-
-		decl.Values[0] = &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: "`" + value + "`",
-		}
+		typeExpr, values = decl.Type, decl.Values
 
 	case *ast.AssignStmt:
 		if len(decl.Lhs) != 1 {
 			debug("more than one name in ast.AssignStmt")
 			return ErrNotFound
 		}
-
-		decl.Rhs[0] = &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: "`" + value + "`",
-		}
+		values = decl.Rhs
 
 	default:
 		debug("can only update *ast.ValueSpec, found %T", obj.Decl)
 		return ErrNotFound
 	}
 
+	ctx := literalContext{typed: typeExpr != nil && printer.isType(typeExpr, value)}
+	lit, err := printer.expr(value, ctx)
+	if err != nil {
+		debug("failed to create literal: %v", err)
+		return ErrNotFound
+	}
+	if str, ok := lit.(*ast.BasicLit); ok && str.Kind == token.STRING {
+		// variables always use a raw string when possible, so that the
+		// expected value is easy to read.
+		if isRawStringSafe(value.String()) {
+			str.Value = "`" + value.String() + "`"
+		}
+	}
+	values[0] = lit
+	return writeFile(filename, fileset, astFile)
+}
+
+func writeFile(filename string, fileset *token.FileSet, astFile *ast.File) error {
 	var buf bytes.Buffer
 	if err := format.Node(&buf, fileset, astFile); err != nil {
 		return fmt.Errorf("failed to format file after update: %w", err)
@@ -169,3 +233,79 @@ func getIdentForExpectedValueArg(expr []ast.Expr) (int, *ast.Ident) {
 	}
 	return -1, nil
 }
+
+// getLiteralExpectedValueArg returns the index of the argument that is a
+// literal value. The second argument is preferred, because by convention
+// the expected value follows the actual value.
+func getLiteralExpectedValueArg(expr []ast.Expr) int {
+	for _, i := range []int{2, 1} {
+		if isLiteral(expr[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isLiteral returns true if expr is a literal value, or a conversion of a
+// literal value to a predeclared type. Composite literals are only literal
+// values when all of their elements are literal values, so that an update does
+// not replace expressions like len(x).
+func isLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if !isLiteralElement(elt) {
+				return false
+			}
+		}
+		return true
+	case *ast.Ident:
+		return e.Obj == nil && (e.Name == "true" || e.Name == "false" || e.Name == "nil")
+	case *ast.UnaryExpr:
+		return (e.Op == token.SUB || e.Op == token.AND) && isLiteral(e.X)
+	case *ast.ParenExpr:
+		return isLiteral(e.X)
+	case *ast.CallExpr:
+		return len(e.Args) == 1 && isConversionType(e.Fun) && isLiteral(e.Args[0])
+	}
+	return false
+}
+
+// isLiteralElement returns true if elt, an element of a composite literal, is a
+// literal value. The key of an element may also be the name of a struct field.
+func isLiteralElement(elt ast.Expr) bool {
+	kv, ok := elt.(*ast.KeyValueExpr)
+	if !ok {
+		return isLiteral(elt)
+	}
+	if key, ok := kv.Key.(*ast.Ident); !ok || key.Obj != nil {
+		if !isLiteral(kv.Key) {
+			return false
+		}
+	}
+	return isLiteral(kv.Value)
+}
+
+func isConversionType(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.Obj != nil {
+			return e.Obj.Kind == ast.Typ
+		}
+		return predeclaredTypes[e.Name]
+	case *ast.ParenExpr:
+		return isConversionType(e.X)
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+		return true
+	}
+	return false
+}
+
+var predeclaredTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"uintptr": true, "float32": true, "float64": true,
+}
@@ -0,0 +1,44 @@
+package source
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestIsLiteral(t *testing.T) {
+	var testcases = []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: `"text"`, expected: true},
+		{expr: `-1`, expected: true},
+		{expr: `int64(3)`, expected: true},
+		{expr: `[]int{1, 2}`, expected: true},
+		{expr: `map[string][]int{"a": {1}}`, expected: true},
+		{expr: `&example{Name: "a", Count: 2}`, expected: true},
+		{expr: `[]int{1, len(x)}`, expected: false},
+		{expr: `map[string]int{key: 1}`, expected: false},
+		{expr: `example{Name: x}`, expected: false},
+		{expr: `[][]string{{"a"}, {key}}`, expected: false},
+		{expr: `len(x)`, expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.expr, func(t *testing.T) {
+			// the expression is parsed in a function, so that identifiers are
+			// resolved to the local variables.
+			src := "package p\nfunc f(x []int, key string) { _ = " + tc.expr + " }\n"
+			file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body := file.Decls[0].(*ast.FuncDecl).Body
+			expr := body.List[0].(*ast.AssignStmt).Rhs[0]
+			if actual := isLiteral(expr); actual != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
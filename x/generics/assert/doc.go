@@ -0,0 +1,4 @@
+/*
+Package assert provides experimental assertions that use type parameters.
+*/
+package assert
@@ -0,0 +1,213 @@
+package assert
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gotest.tools/x/generics/internal/source"
+)
+
+// TableSettings are used to configure the behaviour of [Table].
+type TableSettings struct {
+	// Parallel runs each case in parallel with the other cases, by calling
+	// t.Parallel at the start of each subtest.
+	Parallel bool
+}
+
+// TableOp is a function which accepts and modifies TableSettings.
+type TableOp func(settings *TableSettings)
+
+// WithParallel runs each case in parallel with the other cases.
+func WithParallel() TableOp {
+	return func(settings *TableSettings) {
+		settings.Parallel = true
+	}
+}
+
+const tableFocusEnvVar = "GOTESTTOOLS_TABLE_FOCUS"
+
+// CaseT is the testing.T passed to the run function of [Table] and
+// [TableMap]. Every failure reported with CaseT, including failures reported by
+// assertions, is followed by the position of the test case in the source file,
+// to help find the failing case in a long table.
+type CaseT struct {
+	*testing.T
+	name     string
+	position string
+}
+
+// Fail marks the test case as failed, and logs the position of the case.
+func (t *CaseT) Fail() {
+	t.Helper()
+	t.logPosition()
+	t.T.Fail()
+}
+
+// FailNow marks the test case as failed, logs the position of the case, and
+// stops its execution.
+func (t *CaseT) FailNow() {
+	t.Helper()
+	t.logPosition()
+	t.T.FailNow()
+}
+
+// Error is equivalent to Log followed by Fail.
+func (t *CaseT) Error(args ...interface{}) {
+	t.Helper()
+	t.Log(args...)
+	t.Fail()
+}
+
+// Errorf is equivalent to Logf followed by Fail.
+func (t *CaseT) Errorf(format string, args ...interface{}) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.Fail()
+}
+
+// Fatal is equivalent to Log followed by FailNow.
+func (t *CaseT) Fatal(args ...interface{}) {
+	t.Helper()
+	t.Log(args...)
+	t.FailNow()
+}
+
+// Fatalf is equivalent to Logf followed by FailNow.
+func (t *CaseT) Fatalf(format string, args ...interface{}) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.FailNow()
+}
+
+func (t *CaseT) logPosition() {
+	t.Helper()
+	if t.position != "" {
+		t.Logf("test case %q is defined at %s", t.name, t.position)
+	}
+}
+
+// Table runs each test case in cases as a subtest using t.Run. run is called
+// with the t for the subtest, and the test case.
+//
+// The name of the subtest is the Name (or name) field of the case, when Case
+// is a struct with a string field of that name, or the index of the case.
+// cases may be passed as a composite literal, or as a variable declared with a
+// composite literal, in which case the position of each case in the source file
+// is added to every failure reported by the subtest.
+//
+// Setting the GOTESTTOOLS_TABLE_FOCUS environment variable to the name of a
+// case skips all the other cases.
+//
+// Example:
+//
+//	type testCase struct {
+//		name     string
+//		input    string
+//		expected int
+//	}
+//	assert.Table(t, []testCase{
+//		{name: "empty", input: "", expected: 0},
+//		{name: "one", input: "a", expected: 1},
+//	}, func(t *assert.CaseT, tc testCase) {
+//		assert.Equal(t, len(tc.input), tc.expected)
+//	})
+func Table[Case any](t *testing.T, cases []Case, run func(t *CaseT, tc Case), ops ...TableOp) {
+	t.Helper()
+	const stackIndex = 1 // Table
+	// the position of each case is only used to improve failure messages, so
+	// errors are ignored.
+	elements, _ := source.CallExprArgElements(stackIndex, 1)
+
+	table := make([]tableCase[Case], 0, len(cases))
+	for i, tc := range cases {
+		c := tableCase[Case]{name: caseName(reflect.ValueOf(tc), i), value: tc}
+		if len(elements) == len(cases) {
+			c.position = formatPosition(elements[i].Position)
+		}
+		table = append(table, c)
+	}
+	runTable(t, table, run, ops)
+}
+
+// TableMap runs each test case in cases as a subtest using t.Run, in order of
+// their name. The name of the subtest is the key of the map. Otherwise
+// TableMap is the same as [Table].
+func TableMap[Case any](t *testing.T, cases map[string]Case, run func(t *CaseT, tc Case), ops ...TableOp) {
+	t.Helper()
+	const stackIndex = 1 // TableMap
+	elements, _ := source.CallExprArgElements(stackIndex, 1)
+	positions := make(map[string]string)
+	for _, element := range elements {
+		positions[element.Key] = formatPosition(element.Position)
+	}
+
+	table := make([]tableCase[Case], 0, len(cases))
+	for name, tc := range cases {
+		table = append(table, tableCase[Case]{name: name, value: tc, position: positions[name]})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		return table[i].name < table[j].name
+	})
+	runTable(t, table, run, ops)
+}
+
+type tableCase[Case any] struct {
+	name     string
+	value    Case
+	position string
+}
+
+func runTable[Case any](t *testing.T, table []tableCase[Case], run func(t *CaseT, tc Case), ops []TableOp) {
+	t.Helper()
+	settings := &TableSettings{}
+	for _, op := range ops {
+		op(settings)
+	}
+
+	focus := os.Getenv(tableFocusEnvVar)
+	for _, tc := range table {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if focus != "" && focus != tc.name {
+				t.Skipf("skipped by %s=%s", tableFocusEnvVar, focus)
+			}
+			if settings.Parallel {
+				t.Parallel()
+			}
+			run(&CaseT{T: t, name: tc.name, position: tc.position}, tc.value)
+		})
+	}
+}
+
+// caseName returns the value of the Name or name field of value, or the index
+// of the case if there is no name.
+func caseName(value reflect.Value, index int) string {
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct {
+		for _, name := range []string{"Name", "name"} {
+			field := value.FieldByName(name)
+			if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+				return field.String()
+			}
+		}
+	}
+	return fmt.Sprintf("#%02d", index)
+}
+
+// formatPosition returns the position with a filename that is relative to the
+// working directory, which is the directory of the package being tested.
+func formatPosition(pos token.Position) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil {
+			pos.Filename = rel
+		}
+	}
+	return pos.String()
+}
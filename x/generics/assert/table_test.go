@@ -0,0 +1,87 @@
+package assert
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type tableTestCase struct {
+	name     string
+	input    string
+	expected int
+}
+
+func TestTable(t *testing.T) {
+	var names []string
+	cases := []tableTestCase{
+		{name: "empty", input: "", expected: 0},
+		{input: "a", expected: 1},
+	}
+	Table(t, cases, func(t *CaseT, tc tableTestCase) {
+		names = append(names, t.Name())
+		assert.Equal(t, t.position, map[string]string{
+			"empty": "table_test.go:21:3",
+			"#01":   "table_test.go:22:3",
+		}[t.name])
+		assert.Equal(t, len(tc.input), tc.expected)
+	})
+	assert.DeepEqual(t, names, []string{"TestTable/empty", "TestTable/#01"})
+}
+
+func TestTableMap(t *testing.T) {
+	var names []string
+	TableMap(t, map[string]int{
+		"b": 2,
+		"a": 1,
+	}, func(t *CaseT, tc int) {
+		names = append(names, t.Name())
+		assert.Equal(t, t.position, map[string]string{
+			"a": "table_test.go:39:3",
+			"b": "table_test.go:38:3",
+		}[t.name])
+	})
+	assert.DeepEqual(t, names, []string{"TestTableMap/a", "TestTableMap/b"})
+}
+
+func TestTable_Focus(t *testing.T) {
+	t.Setenv(tableFocusEnvVar, "#01")
+	var names []string
+	Table(t, []string{"one", "two"}, func(t *CaseT, tc string) {
+		names = append(names, tc)
+	})
+	assert.DeepEqual(t, names, []string{"two"})
+}
+
+const tableFailingEnvVar = "GOTESTTOOLS_TABLE_FAILING"
+
+// TestTable_Failing only runs when it is run by TestTable_FailureMessages.
+func TestTable_Failing(t *testing.T) {
+	if os.Getenv(tableFailingEnvVar) == "" {
+		t.Skip("run by TestTable_FailureMessages")
+	}
+	Table(t, []tableTestCase{
+		{name: "fail", input: "a", expected: 2},
+	}, func(t *CaseT, tc tableTestCase) {
+		assert.Check(t, len(tc.input) == tc.expected)
+		t.Errorf("second failure")
+	})
+}
+
+func TestTable_FailureMessages(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTable_Failing$", "-test.v")
+	cmd.Env = append(os.Environ(), tableFailingEnvVar+"=1")
+	out, err := cmd.CombinedOutput()
+	assert.Assert(t, err != nil, string(out))
+
+	position := `test case "fail" is defined at table_test.go:67:3`
+	assert.Assert(t, strings.Count(string(out), position) == 2, string(out))
+	for _, msg := range []string{"len(tc.input) is not tc.expected", "second failure"} {
+		idx := strings.Index(string(out), msg)
+		assert.Assert(t, idx >= 0, string(out))
+		assert.Assert(t, strings.Contains(string(out)[idx:], position), string(out))
+	}
+}
@@ -0,0 +1,120 @@
+// Package source finds the position of composite literals in source code.
+package source
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"runtime"
+	"strconv"
+)
+
+// LiteralElement is an element of a composite literal.
+type LiteralElement struct {
+	// Key is the value of the key of an element of a map literal when the key
+	// is a string literal. Otherwise Key is empty.
+	Key string
+	// Position of the element in the source file.
+	Position token.Position
+}
+
+// CallExprArgElements returns the elements of the composite literal that is
+// passed as the argument at argPos to the call expression at the index in the
+// call stack. The argument may be a composite literal, or a variable that was
+// declared with a composite literal.
+func CallExprArgElements(stackIndex int, argPos int) ([]LiteralElement, error) {
+	_, filename, line, ok := runtime.Caller(stackIndex + 1)
+	if !ok {
+		return nil, errors.New("failed to get call stack")
+	}
+
+	fileset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileset, filename, nil, parser.AllErrors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source file %s: %w", filename, err)
+	}
+
+	expr := callExprAtLine(fileset, astFile, line)
+	if expr == nil {
+		return nil, fmt.Errorf("call from %s:%d: failed to find expression", filename, line)
+	}
+	if argPos >= len(expr.Args) {
+		return nil, errors.New("failed to find expression")
+	}
+	lit := compositeLitFromExpr(expr.Args[argPos])
+	if lit == nil {
+		return nil, errors.New("argument is not a composite literal")
+	}
+
+	elements := make([]LiteralElement, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		element := LiteralElement{Position: fileset.Position(elt.Pos())}
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.BasicLit); ok && key.Kind == token.STRING {
+				element.Key, _ = strconv.Unquote(key.Value)
+			}
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+// callExprAtLine returns the first call expression in the first node that
+// starts at line.
+func callExprAtLine(fileset *token.FileSet, astFile *ast.File, line int) *ast.CallExpr {
+	var node ast.Node
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		switch {
+		case n == nil || node != nil:
+			return false
+		case fileset.Position(n.Pos()).Line == line:
+			node = n
+			return false
+		}
+		return true
+	})
+	if node == nil {
+		return nil
+	}
+
+	var expr *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if expr != nil {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			expr = call
+			return false
+		}
+		return true
+	})
+	return expr
+}
+
+// compositeLitFromExpr returns expr if it is a composite literal, or the
+// composite literal used to declare expr if it is a variable.
+func compositeLitFromExpr(expr ast.Expr) *ast.CompositeLit {
+	switch typed := expr.(type) {
+	case *ast.CompositeLit:
+		return typed
+	case *ast.Ident:
+		if typed.Obj == nil {
+			return nil
+		}
+		var values []ast.Expr
+		switch decl := typed.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			values = decl.Values
+		case *ast.AssignStmt:
+			values = decl.Rhs
+		}
+		if len(values) != 1 {
+			return nil
+		}
+		lit, _ := values[0].(*ast.CompositeLit)
+		return lit
+	}
+	return nil
+}
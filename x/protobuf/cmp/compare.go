@@ -0,0 +1,49 @@
+/*
+Package cmp provides Comparisons for protobuf messages, for use with
+[gotest.tools/v3/assert.Assert] and [gotest.tools/v3/assert.Check].
+*/
+package cmp // import "gotest.tools/x/protobuf/cmp"
+
+import (
+	gocmp "github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert/cmp"
+)
+
+// ProtoEqual compares two protobuf messages using go-cmp with the
+// protocmp.Transform option, which compares the messages by their fields
+// instead of the internal state of the generated structs. Options from
+// [gotest.tools/x/protobuf/opt] or protocmp can be used to change how fields
+// are compared.
+//
+// Failures are reported as a unified diff of the messages in the protobuf
+// text format. The diff includes all the fields of the messages, including any
+// fields that were ignored by opts.
+func ProtoEqual(x, y proto.Message, opts ...gocmp.Option) cmp.Comparison {
+	return func() cmp.Result {
+		opts := append([]gocmp.Option{protocmp.Transform()}, opts...)
+		diff := gocmp.Diff(x, y, opts...)
+		if diff == "" {
+			return cmp.ResultSuccess
+		}
+
+		textX, textY := formatText(x), formatText(y)
+		if textX == textY {
+			// The difference is not visible in the text format, for example
+			// unknown fields, so use the diff from go-cmp.
+			return cmp.ResultFailure("\n" + diff)
+		}
+		return cmp.Equal(textX, textY)()
+	}
+}
+
+// formatText returns the message in the protobuf text format, with a trailing
+// newline so that the text is always compared as multiple lines.
+func formatText(m proto.Message) string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return "<nil>\n"
+	}
+	return prototext.MarshalOptions{Multiline: true, Indent: "  "}.Format(m) + "\n"
+}
@@ -0,0 +1,93 @@
+package cmp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestProtoEqual_Success(t *testing.T) {
+	x := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("id"),
+		Number: proto.Int32(1),
+	}
+	y := proto.Clone(x)
+	assert.Check(t, ProtoEqual(x, y))
+	assert.Check(t, ProtoEqual(nil, nil))
+}
+
+func TestProtoEqual_Failure(t *testing.T) {
+	got := &descriptorpb.DescriptorProto{
+		Name: proto.String("User"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{Name: proto.String("id"), Number: proto.Int32(1)},
+		},
+	}
+	want := &descriptorpb.DescriptorProto{
+		Name: proto.String("User"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{Name: proto.String("id"), Number: proto.Int32(2)},
+		},
+	}
+
+	fakeT := &fakeTestingT{}
+	assert.Check(fakeT, ProtoEqual(got, want))
+	assert.Assert(t, fakeT.failed)
+
+	expected := `assertion failed: 
+--- got
++++ want
+@@ -2,5 +2,5 @@
+ field: {
+   name: "id"
+-  number: 1
++  number: 2
+ }
+ 
+`
+	assert.Equal(t, normalizeText(fakeT.msg), expected)
+}
+
+func TestProtoEqual_FailureWithNil(t *testing.T) {
+	fakeT := &fakeTestingT{}
+	got := &descriptorpb.FieldDescriptorProto{Name: proto.String("id")}
+	assert.Check(fakeT, ProtoEqual(got, nil))
+	assert.Assert(t, fakeT.failed)
+	assert.Assert(t, strings.Contains(fakeT.msg, "+<nil>"), fakeT.msg)
+}
+
+func TestProtoEqual_WithOptions(t *testing.T) {
+	x := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), JsonName: proto.String("id")}
+	y := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), JsonName: proto.String("ID")}
+	assert.Check(t, ProtoEqual(x, y,
+		protocmp.IgnoreFields(&descriptorpb.FieldDescriptorProto{}, "json_name")))
+}
+
+// normalizeText removes the whitespace that the protobuf text format randomly
+// adds after the field name, to discourage comparing the output byte by byte.
+func normalizeText(s string) string {
+	return regexp.MustCompile(`: +`).ReplaceAllString(s, ": ")
+}
+
+type fakeTestingT struct {
+	failed bool
+	msg    string
+}
+
+func (t *fakeTestingT) Fail() {
+	t.failed = true
+}
+
+func (t *fakeTestingT) FailNow() {
+	t.failed = true
+}
+
+func (t *fakeTestingT) Log(args ...interface{}) {
+	t.msg = fmt.Sprint(args...)
+}
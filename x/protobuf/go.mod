@@ -0,0 +1,9 @@
+module gotest.tools/x/protobuf
+
+go 1.17
+
+require (
+	github.com/google/go-cmp v0.5.9
+	google.golang.org/protobuf v1.28.1
+	gotest.tools/v3 v3.3.0
+)
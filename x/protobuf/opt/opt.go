@@ -0,0 +1,105 @@
+/*
+Package opt provides common go-cmp options for comparing protobuf messages
+with [gotest.tools/x/protobuf/cmp.ProtoEqual].
+*/
+package opt // import "gotest.tools/x/protobuf/opt"
+
+import (
+	"bytes"
+	"reflect"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// IgnoreFields ignores the fields of message with the given names. The names
+// are the names of the fields in the .proto file, not the names of the fields
+// in the generated Go struct. message is only used to find the type of the
+// message, the value of its fields are not used.
+//
+// IgnoreFields panics if message does not have a field with one of the names.
+func IgnoreFields(message proto.Message, names ...string) gocmp.Option {
+	return protocmp.IgnoreFields(message, toProtoNames(names)...)
+}
+
+// EquateDefaults treats unset fields as equal to fields that are set to their
+// default value. An unset scalar field is equal to the default value of the
+// field, and an unset message field is equal to a message where every field is
+// unset or set to its default value. Elements of repeated fields and values of
+// map fields are always compared.
+func EquateDefaults() gocmp.Option {
+	return gocmp.FilterPath(func(path gocmp.Path) bool {
+		index, ok := path.Index(-1).(gocmp.MapIndex)
+		if !ok || path.Index(-2).Type() != messageType {
+			return false
+		}
+		x, y := path.Index(-2).Values()
+		name := index.Key().String()
+		return isDefaultField(x.Interface().(protocmp.Message), name) &&
+			isDefaultField(y.Interface().(protocmp.Message), name)
+	}, gocmp.Ignore())
+}
+
+var messageType = reflect.TypeOf(protocmp.Message(nil))
+
+// isDefaultField returns true if the field of message with the name is unset
+// or set to the default value.
+func isDefaultField(message protocmp.Message, name string) bool {
+	desc := message.Descriptor()
+	if desc == nil {
+		return false
+	}
+	field := desc.Fields().ByTextName(name)
+	if field == nil {
+		// extension fields, unknown fields, and the metadata added by
+		// protocmp.Transform are always compared.
+		return false
+	}
+	return isDefaultValue(message.Unwrap().ProtoReflect(), field)
+}
+
+func isDefaultValue(message protoreflect.Message, field protoreflect.FieldDescriptor) bool {
+	switch {
+	case !message.Has(field):
+		return true
+	case field.IsList() || field.IsMap():
+		// lists and maps are only set when they have at least one element
+		return false
+	case field.Message() != nil:
+		return isDefaultMessage(message.Get(field).Message())
+	case field.Kind() == protoreflect.BytesKind:
+		return bytes.Equal(message.Get(field).Bytes(), field.Default().Bytes())
+	default:
+		return message.Get(field).Interface() == field.Default().Interface()
+	}
+}
+
+func isDefaultMessage(message protoreflect.Message) bool {
+	isDefault := true
+	message.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		isDefault = isDefaultValue(message, field)
+		return isDefault
+	})
+	return isDefault
+}
+
+// RepeatedAsSets compares the repeated fields of message with the given names
+// without considering the order of the elements. The elements are sorted
+// before they are compared, so the number of times each element appears must
+// still be the same. The names are the names of the fields in the .proto file.
+//
+// RepeatedAsSets panics if message does not have a repeated field with one of
+// the names.
+func RepeatedAsSets(message proto.Message, names ...string) gocmp.Option {
+	return protocmp.SortRepeatedFields(message, toProtoNames(names)...)
+}
+
+func toProtoNames(names []string) []protoreflect.Name {
+	result := make([]protoreflect.Name, 0, len(names))
+	for _, name := range names {
+		result = append(result, protoreflect.Name(name))
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package opt
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gotest.tools/v3/assert"
+	protocmp "gotest.tools/x/protobuf/cmp"
+)
+
+func TestIgnoreFields(t *testing.T) {
+	x := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), JsonName: proto.String("id")}
+	y := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), JsonName: proto.String("ID")}
+
+	assert.Check(t, !protocmp.ProtoEqual(x, y)().Success())
+	assert.Check(t, protocmp.ProtoEqual(x, y, IgnoreFields(x, "json_name")))
+}
+
+func TestIgnoreFields_UnknownField(t *testing.T) {
+	defer func() {
+		assert.Check(t, recover() != nil, "expected a panic")
+	}()
+	IgnoreFields(&descriptorpb.FieldDescriptorProto{}, "not_a_field")
+}
+
+func TestEquateDefaults(t *testing.T) {
+	unset := &descriptorpb.FileDescriptorProto{Name: proto.String("a.proto")}
+	defaults := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("a.proto"),
+		Options: &descriptorpb.FileOptions{JavaMultipleFiles: proto.Bool(false)},
+	}
+	changed := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("a.proto"),
+		Options: &descriptorpb.FileOptions{JavaMultipleFiles: proto.Bool(true)},
+	}
+
+	assert.Check(t, !protocmp.ProtoEqual(unset, defaults)().Success())
+	assert.Check(t, protocmp.ProtoEqual(unset, defaults, EquateDefaults()))
+	assert.Check(t, protocmp.ProtoEqual(unset, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("a.proto"),
+		Options: &descriptorpb.FileOptions{},
+	}, EquateDefaults()))
+	assert.Check(t, !protocmp.ProtoEqual(unset, changed, EquateDefaults())().Success())
+}
+
+func TestRepeatedAsSets(t *testing.T) {
+	x := &descriptorpb.FileDescriptorProto{Dependency: []string{"a.proto", "b.proto"}}
+	y := &descriptorpb.FileDescriptorProto{Dependency: []string{"b.proto", "a.proto"}}
+	z := &descriptorpb.FileDescriptorProto{Dependency: []string{"b.proto", "c.proto"}}
+
+	assert.Check(t, !protocmp.ProtoEqual(x, y)().Success())
+	assert.Check(t, protocmp.ProtoEqual(x, y, RepeatedAsSets(x, "dependency")))
+	assert.Check(t, !protocmp.ProtoEqual(x, z, RepeatedAsSets(x, "dependency"))().Success())
+}